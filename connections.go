@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/camirmas/go_stop/models"
+	"github.com/camirmas/go_stop/relay"
+)
+
+// stoneConnection paginates stones through the first/after args of a Relay
+// connection field, using each stone's index in stones as its cursor's
+// underlying order key.
+func stoneConnection(stones []models.Stone, args map[string]interface{}) (map[string]interface{}, error) {
+	first, _ := args["first"].(int)
+	if first <= 0 {
+		first = len(stones)
+	}
+
+	start := 0
+	if after, _ := args["after"].(string); after != "" {
+		key, err := relay.DecodeCursor(after)
+		if err != nil {
+			return nil, err
+		}
+		idx, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, err
+		}
+		start = idx + 1
+	}
+
+	if start < 0 {
+		start = 0
+	}
+	if start > len(stones) {
+		start = len(stones)
+	}
+
+	end := start + first
+	if end > len(stones) {
+		end = len(stones)
+	}
+
+	page := stones[start:end]
+	edges := make([]map[string]interface{}, len(page))
+	for i, s := range page {
+		edges[i] = map[string]interface{}{
+			"cursor": relay.EncodeCursor(start + i),
+			"node":   s,
+		}
+	}
+
+	endCursor := ""
+	if len(edges) > 0 {
+		endCursor = edges[len(edges)-1]["cursor"].(string)
+	}
+
+	return map[string]interface{}{
+		"edges": edges,
+		"pageInfo": map[string]interface{}{
+			"hasNextPage": end < len(stones),
+			"endCursor":   endCursor,
+		},
+	}, nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/camirmas/go_stop/models"
+	"github.com/camirmas/go_stop/relay"
+)
+
+func TestStoneConnection_NegativeCursorClamped(t *testing.T) {
+	stones := []models.Stone{
+		{X: 0, Y: 0, Color: "black"},
+		{X: 1, Y: 0, Color: "white"},
+	}
+
+	page, err := stoneConnection(stones, map[string]interface{}{
+		"after": relay.EncodeCursor(-5),
+	})
+	if err != nil {
+		t.Fatalf("stoneConnection returned error: %v", err)
+	}
+
+	edges := page["edges"].([]map[string]interface{})
+	if len(edges) != len(stones) {
+		t.Fatalf("got %d edges, want %d", len(edges), len(stones))
+	}
+}
+
+func TestStoneConnection_AfterPastEnd(t *testing.T) {
+	stones := []models.Stone{{X: 0, Y: 0, Color: "black"}}
+
+	page, err := stoneConnection(stones, map[string]interface{}{
+		"after": relay.EncodeCursor(50),
+	})
+	if err != nil {
+		t.Fatalf("stoneConnection returned error: %v", err)
+	}
+
+	edges := page["edges"].([]map[string]interface{})
+	if len(edges) != 0 {
+		t.Fatalf("got %d edges, want 0", len(edges))
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/camirmas/go_stop/relay"
+)
+
+// parseGameId decodes a Game's opaque global ID, as received over
+// GraphQL's ID scalar, into the int used internally and by Postgres.
+func parseGameId(raw string) (int, error) {
+	typeName, localId, err := relay.FromGlobalID(raw)
+	if err != nil {
+		return 0, err
+	}
+	if typeName != "Game" {
+		return 0, fmt.Errorf("not a game id: %q", raw)
+	}
+
+	return strconv.Atoi(localId)
+}
@@ -0,0 +1,43 @@
+package main
+
+import "github.com/graphql-go/graphql"
+
+// Input object types for mutations, declared once and reused so the
+// schema can grow optional fields onto an existing operation without
+// breaking clients that already send it.
+var (
+	createUserInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateUserInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"username":             &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"email":                &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"password":             &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"passwordConfirmation": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	logInInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "LogInInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"username": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"password": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	createGameInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateGameInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"opponentUsername": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"koRule":           &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	addStoneInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "AddStoneInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"gameId": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.ID)},
+			"x":      &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+			"y":      &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+)
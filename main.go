@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/camirmas/go_stop/models"
+	"github.com/camirmas/go_stop/pubsub"
+	"github.com/camirmas/go_stop/rules"
+	"github.com/camirmas/go_stop/server"
+	"github.com/camirmas/go_stop/storage"
+	"github.com/camirmas/go_stop/storage/mongo"
+	"github.com/camirmas/go_stop/storage/postgres"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// newRepository builds the storage.Repository selected by STORAGE_BACKEND
+// (defaulting to postgres), so the same schema can run against either
+// store without any resolver changes.
+func newRepository() (storage.Repository, error) {
+	switch os.Getenv("STORAGE_BACKEND") {
+	case "mongo":
+		client, err := mongodriver.Connect(context.Background(), options.Client().ApplyURI(os.Getenv("MONGO_URL")))
+		if err != nil {
+			return nil, err
+		}
+		return mongo.New(context.Background(), client.Database(os.Getenv("MONGO_DB")))
+	default:
+		if err := models.Connect(os.Getenv("DATABASE_URL")); err != nil {
+			return nil, err
+		}
+		return postgres.New(models.DB), nil
+	}
+}
+
+func csvEnv(name string) []string {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return nil
+	}
+
+	parts := strings.Split(raw, ",")
+	for i, p := range parts {
+		parts[i] = strings.TrimSpace(p)
+	}
+	return parts
+}
+
+func main() {
+	repo, err := newRepository()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rules.Hub = pubsub.NewHub()
+
+	mux := http.NewServeMux()
+
+	server.RegisterGraphQLService(mux, schema, server.Config{
+		Repo:           repo,
+		CorsOrigins:    csvEnv("CORS_ORIGINS"),
+		VirtualHosts:   csvEnv("VIRTUAL_HOSTS"),
+		EnableGraphiQL: os.Getenv("ENABLE_GRAPHIQL") == "true",
+	})
+
+	mux.Handle("/subscriptions", server.SubscriptionHandler(schema))
+
+	addr := ":" + os.Getenv("PORT")
+	log.Printf("listening on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, mux))
+}
@@ -0,0 +1,21 @@
+package models
+
+import "context"
+
+type userContextKey struct{}
+
+var currentUserKey = userContextKey{}
+
+// NewContext returns a copy of ctx carrying user, so that authentication
+// middleware can hand the authenticated caller to resolvers without
+// threading it through every Args map.
+func NewContext(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, currentUserKey, user)
+}
+
+// UserFromContext extracts the *User injected by NewContext, or nil if
+// the request was unauthenticated.
+func UserFromContext(ctx context.Context) *User {
+	user, _ := ctx.Value(currentUserKey).(*User)
+	return user
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"database/sql"
+
+	_ "github.com/lib/pq"
+)
+
+// DB is the package-level Postgres handle that main wraps in the postgres
+// storage backend. It is opened once at startup via Connect.
+var DB *sql.DB
+
+// Connect opens the Postgres connection pool used by the postgres storage
+// backend.
+func Connect(dataSourceName string) error {
+	db, err := sql.Open("postgres", dataSourceName)
+	if err != nil {
+		return err
+	}
+
+	if err := db.Ping(); err != nil {
+		return err
+	}
+
+	DB = db
+	return nil
+}
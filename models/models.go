@@ -0,0 +1,85 @@
+/*
+Package models holds the domain types shared across the rules engine, the
+resolvers, and the GraphQL schema. These are plain structs; persistence is
+handled separately so the same types can be hydrated from Postgres, tests,
+or any other store.
+*/
+package models
+
+// User is a registered player account.
+type User struct {
+	Id       int    `json:"id" db:"id"`
+	Username string `json:"username" db:"username"`
+	Email    string `json:"email" db:"email"`
+	Password string `json:"-" db:"password"`
+}
+
+// AuthUser is returned from createUser/logIn, pairing a User with a signed
+// session token.
+type AuthUser struct {
+	User *User
+	Jwt  string
+}
+
+// Stone is a single placed piece on a Board. A Stone with an empty Color
+// represents an unoccupied point.
+type Stone struct {
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Color string `json:"color"`
+}
+
+// Board is the physical Go board for a Game.
+type Board struct {
+	GameId        int     `json:"-"`
+	Size          int     `json:"size"`
+	Stones        []Stone `json:"stones"`
+	LastTaker     *Stone  `json:"lastTaker"`
+	BlackCaptures int     `json:"blackCaptures"`
+	WhiteCaptures int     `json:"whiteCaptures"`
+}
+
+// Player is a User's seat within a particular Game.
+type Player struct {
+	Id        int    `json:"id"`
+	GameId    int    `json:"gameId"`
+	UserId    int    `json:"-"`
+	Status    string `json:"status"`
+	Color     string `json:"color"`
+	HasPassed bool   `json:"hasPassed"`
+	User      *User  `json:"user"`
+}
+
+// Move is a single recorded turn in a Game's history, in play order.
+// Pass moves carry no coordinate.
+type Move struct {
+	Color string `json:"color"`
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Pass  bool   `json:"pass"`
+}
+
+// Game tracks a single match between two Players.
+type Game struct {
+	Id           int      `json:"id"`
+	Status       string   `json:"status"`
+	PlayerTurnId int      `json:"playerTurnId"`
+	Players      []Player `json:"players"`
+	Board        *Board   `json:"board"`
+	Komi         float64  `json:"komi"`
+	Moves        []Move   `json:"-"`
+
+	// KoRule selects how rules.Run enforces ko: "superko" rejects any move
+	// that recreates a position found anywhere in PositionHistory, while
+	// any other value (the default is empty) means simple ko, which rejects
+	// only an immediate recapture.
+	KoRule          string   `json:"-"`
+	PositionHistory []uint64 `json:"-"`
+}
+
+// GameEndedEvent is published on rules.Hub when a Game finishes (e.g. two
+// consecutive passes), so a gameEnded subscriber can be told apart from
+// the ordinary Game snapshot published after every move or pass.
+type GameEndedEvent struct {
+	Game *Game
+}
@@ -1,23 +1,19 @@
 package main
 
+import (
+	"github.com/camirmas/go_stop/resolvers"
+	"github.com/graphql-go/graphql"
+)
+
 var (
-	mutationType := graphql.NewObject(graphql.ObjectConfig{
+	mutationType = graphql.NewObject(graphql.ObjectConfig{
 		Name: "Mutation",
 		Fields: graphql.Fields{
 			"createUser": &graphql.Field{
 				Type: tokenType,
 				Args: graphql.FieldConfigArgument{
-					"username": &graphql.ArgumentConfig{
-						Type: graphql.NewNonNull(graphql.String),
-					},
-					"email": &graphql.ArgumentConfig{
-						Type: graphql.NewNonNull(graphql.String),
-					},
-					"password": &graphql.ArgumentConfig{
-						Type: graphql.NewNonNull(graphql.String),
-					},
-					"passwordConfirmation": &graphql.ArgumentConfig{
-						Type: graphql.NewNonNull(graphql.String),
+					"input": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(createUserInputType),
 					},
 				},
 				Resolve: resolvers.CreateUser,
@@ -26,8 +22,8 @@ var (
 			"createGame": &graphql.Field{
 				Type: gameType,
 				Args: graphql.FieldConfigArgument{
-					"opponentUsername": &graphql.ArgumentConfig{
-						Type: graphql.NewNonNull(graphql.String),
+					"input": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(createGameInputType),
 					},
 				},
 				Resolve: resolvers.CreateGame,
@@ -46,27 +42,28 @@ var (
 			"logIn": &graphql.Field{
 				Type: tokenType,
 				Args: graphql.FieldConfigArgument{
-					"username": &graphql.ArgumentConfig{
-						Type: graphql.NewNonNull(graphql.String),
+					"input": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(logInInputType),
 					},
-					"password": &graphql.ArgumentConfig{
+				},
+				Resolve: resolvers.LogIn,
+			},
+
+			"loadSGF": &graphql.Field{
+				Type: gameType,
+				Args: graphql.FieldConfigArgument{
+					"data": &graphql.ArgumentConfig{
 						Type: graphql.NewNonNull(graphql.String),
 					},
 				},
-				Resolve: resolvers.LogIn,
+				Resolve: resolvers.LoadSGF,
 			},
 
 			"addStone": &graphql.Field{
 				Type: gameType,
 				Args: graphql.FieldConfigArgument{
-					"gameId": &graphql.ArgumentConfig{
-						Type: graphql.NewNonNull(graphql.ID),
-					},
-					"x": &graphql.ArgumentConfig{
-						Type: graphql.NewNonNull(graphql.Int),
-					},
-					"y": &graphql.ArgumentConfig{
-						Type: graphql.NewNonNull(graphql.Int),
+					"input": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(addStoneInputType),
 					},
 				},
 				Resolve: resolvers.AddStone,
@@ -0,0 +1,70 @@
+/*
+Package pubsub is a small in-memory broker used to fan out game events
+(stones placed, passes, captures, endings) to any number of subscribers
+without the caller needing to poll. Topics are scoped per game id so a
+client only receives events for the game it subscribed to.
+*/
+package pubsub
+
+import "sync"
+
+// Event is a single published occurrence, tagged with the game it
+// happened in.
+type Event struct {
+	GameId int
+	Data   interface{}
+}
+
+// Hub holds one topic (channel fan-out list) per game id.
+type Hub struct {
+	mu     sync.Mutex
+	topics map[int][]chan Event
+}
+
+// NewHub creates an empty Hub.
+func NewHub() *Hub {
+	return &Hub{topics: make(map[int][]chan Event)}
+}
+
+// Subscribe registers a new subscriber for gameId and returns a channel
+// that receives every Event published to it. The returned unsubscribe
+// func must be called (e.g. on context cancellation) to stop delivery
+// and release the channel.
+func (h *Hub) Subscribe(gameId int) (ch chan Event, unsubscribe func()) {
+	ch = make(chan Event, 8)
+
+	h.mu.Lock()
+	h.topics[gameId] = append(h.topics[gameId], ch)
+	h.mu.Unlock()
+
+	unsubscribe = func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		subs := h.topics[gameId]
+		for i, sub := range subs {
+			if sub == ch {
+				h.topics[gameId] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}
+
+	return ch, unsubscribe
+}
+
+// Publish sends data to every current subscriber of gameId. Publish never
+// blocks on a slow subscriber; a full subscriber channel drops the event.
+func (h *Hub) Publish(gameId int, data interface{}) {
+	h.mu.Lock()
+	subs := append([]chan Event(nil), h.topics[gameId]...)
+	h.mu.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- Event{GameId: gameId, Data: data}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/camirmas/go_stop/resolvers"
+	"github.com/graphql-go/graphql"
+)
+
+var (
+	queryType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"node": &graphql.Field{
+				Type: nodeInterface,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.ID),
+					},
+				},
+				Resolve: resolvers.Node,
+			},
+
+			"game": &graphql.Field{
+				Type: gameType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.ID),
+					},
+				},
+				Resolve: resolvers.Game,
+			},
+		},
+	})
+)
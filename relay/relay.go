@@ -0,0 +1,56 @@
+/*
+Package relay implements the small pieces of the Relay Object
+Identification spec this schema needs: opaque global IDs of the form
+base64("Type:localID"), and opaque pagination cursors wrapping an
+underlying order key.
+*/
+package relay
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// ToGlobalID encodes a type name and local ID into an opaque global ID.
+func ToGlobalID(typeName string, localID interface{}) string {
+	raw := fmt.Sprintf("%s:%v", typeName, localID)
+	return base64.StdEncoding.EncodeToString([]byte(raw))
+}
+
+// FromGlobalID decodes a global ID produced by ToGlobalID back into its
+// type name and local ID.
+func FromGlobalID(globalID string) (typeName, localID string, err error) {
+	raw, err := base64.StdEncoding.DecodeString(globalID)
+	if err != nil {
+		return "", "", fmt.Errorf("relay: invalid global id %q: %w", globalID, err)
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("relay: malformed global id %q", globalID)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// EncodeCursor wraps an underlying order key (e.g. a row ID or index) as
+// an opaque pagination cursor.
+func EncodeCursor(key interface{}) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("cursor:%v", key)))
+}
+
+// DecodeCursor reverses EncodeCursor, returning the underlying order key.
+func DecodeCursor(cursor string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("relay: invalid cursor %q: %w", cursor, err)
+	}
+
+	key := strings.TrimPrefix(string(raw), "cursor:")
+	if key == string(raw) {
+		return "", fmt.Errorf("relay: malformed cursor %q", cursor)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,20 @@
+package resolvers
+
+import (
+	"os"
+	"time"
+
+	"github.com/camirmas/go_stop/models"
+	"github.com/dgrijalva/jwt-go"
+)
+
+func signToken(user *models.User) (string, error) {
+	claims := jwt.MapClaims{
+		"sub": user.Id,
+		"exp": time.Now().Add(24 * time.Hour).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+}
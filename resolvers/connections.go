@@ -0,0 +1,64 @@
+package resolvers
+
+import (
+	"strconv"
+
+	"github.com/camirmas/go_stop/relay"
+	"github.com/camirmas/go_stop/storage"
+	"github.com/graphql-go/graphql"
+)
+
+const defaultPageSize = 10
+
+// UserGames returns a Relay connection page of the games userId has
+// played, ordered by id, optionally starting after an opaque cursor.
+func UserGames(p graphql.ResolveParams, userId int, first int, after string) (map[string]interface{}, error) {
+	repo := storage.FromContext(p.Context)
+
+	if first <= 0 {
+		first = defaultPageSize
+	}
+
+	afterId := 0
+	if after != "" {
+		key, err := relay.DecodeCursor(after)
+		if err != nil {
+			return nil, err
+		}
+		afterId, err = strconv.Atoi(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	games, err := repo.ListUserGames(userId, afterId, first+1)
+	if err != nil {
+		return nil, err
+	}
+
+	hasNextPage := len(games) > first
+	if hasNextPage {
+		games = games[:first]
+	}
+
+	edges := make([]map[string]interface{}, len(games))
+	for i, game := range games {
+		edges[i] = map[string]interface{}{
+			"cursor": relay.EncodeCursor(game.Id),
+			"node":   game,
+		}
+	}
+
+	endCursor := ""
+	if len(edges) > 0 {
+		endCursor = edges[len(edges)-1]["cursor"].(string)
+	}
+
+	return map[string]interface{}{
+		"edges": edges,
+		"pageInfo": map[string]interface{}{
+			"hasNextPage": hasNextPage,
+			"endCursor":   endCursor,
+		},
+	}, nil
+}
@@ -0,0 +1,37 @@
+package resolvers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/camirmas/go_stop/relay"
+	"github.com/camirmas/go_stop/storage"
+	"github.com/graphql-go/graphql"
+)
+
+// Node resolves the top-level `node(id: ID!)` query by decoding a global
+// ID and dispatching to whichever loader owns that type.
+func Node(p graphql.ResolveParams) (interface{}, error) {
+	repo := storage.FromContext(p.Context)
+
+	typeName, localId, err := relay.FromGlobalID(p.Args["id"].(string))
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(localId)
+	if err != nil {
+		return nil, err
+	}
+
+	switch typeName {
+	case "User":
+		return repo.GetUserById(id)
+	case "Player":
+		return repo.GetPlayer(id)
+	case "Game":
+		return repo.GetGame(id)
+	default:
+		return nil, fmt.Errorf("node: unknown type %q", typeName)
+	}
+}
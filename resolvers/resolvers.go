@@ -0,0 +1,363 @@
+/*
+Package resolvers implements the Resolve functions wired into the GraphQL
+schema in the main package. Each resolver is responsible for validating
+its arguments, talking to the storage.Repository injected into the
+request context, and returning the models.* value the matching GraphQL
+type expects.
+*/
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/camirmas/go_stop/models"
+	"github.com/camirmas/go_stop/relay"
+	"github.com/camirmas/go_stop/rules"
+	"github.com/camirmas/go_stop/sgf"
+	"github.com/camirmas/go_stop/storage"
+	"github.com/camirmas/go_stop/validators"
+	"github.com/graphql-go/graphql"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// CreateUser registers a new account and returns a signed session token.
+func CreateUser(p graphql.ResolveParams) (interface{}, error) {
+	repo := storage.FromContext(p.Context)
+
+	input := p.Args["input"].(map[string]interface{})
+	username := input["username"].(string)
+	email := input["email"].(string)
+	password := input["password"].(string)
+	passwordConfirmation := input["passwordConfirmation"].(string)
+
+	if errs := validators.Validate(validators.CreateUserInput{
+		Username:             username,
+		Email:                email,
+		Password:             password,
+		PasswordConfirmation: passwordConfirmation,
+	}); len(errs) > 0 {
+		return nil, &validators.ValidationError{FieldErrors: errs}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{Username: username, Email: email, Password: string(hash)}
+
+	if err := repo.CreateUser(user); err != nil {
+		return nil, err
+	}
+
+	token, err := signToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuthUser{User: user, Jwt: token}, nil
+}
+
+// LogIn verifies credentials and returns a signed session token.
+func LogIn(p graphql.ResolveParams) (interface{}, error) {
+	repo := storage.FromContext(p.Context)
+
+	input := p.Args["input"].(map[string]interface{})
+	username := input["username"].(string)
+	password := input["password"].(string)
+
+	if errs := validators.Validate(validators.LogInInput{Username: username, Password: password}); len(errs) > 0 {
+		return nil, &validators.ValidationError{FieldErrors: errs}
+	}
+
+	user, err := repo.GetUserByUsername(username)
+	if err != nil {
+		return nil, errors.New("invalid username or password")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, errors.New("invalid username or password")
+	}
+
+	token, err := signToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuthUser{User: user, Jwt: token}, nil
+}
+
+// CreateGame starts a new game between the requesting user (who plays
+// black) and an opponent (white), and gives the first turn to black.
+func CreateGame(p graphql.ResolveParams) (interface{}, error) {
+	user := models.UserFromContext(p.Context)
+	if user == nil {
+		return nil, errors.New("must be logged in")
+	}
+
+	repo := storage.FromContext(p.Context)
+
+	input := p.Args["input"].(map[string]interface{})
+	opponentUsername := input["opponentUsername"].(string)
+	koRule, _ := input["koRule"].(string)
+
+	if errs := validators.Validate(validators.CreateGameInput{OpponentUsername: opponentUsername, KoRule: koRule}); len(errs) > 0 {
+		return nil, &validators.ValidationError{FieldErrors: errs}
+	}
+
+	opponent, err := repo.GetUserByUsername(opponentUsername)
+	if err != nil {
+		return nil, errors.New("opponent not found")
+	}
+
+	game := &models.Game{
+		Status: "active",
+		Board:  &models.Board{Size: 19, Stones: []models.Stone{}},
+		KoRule: koRule,
+	}
+	game.PositionHistory = []uint64{rules.PositionHash(game.Board.Stones)}
+
+	if err := repo.CreateGame(game); err != nil {
+		return nil, err
+	}
+
+	black := &models.Player{GameId: game.Id, UserId: user.Id, Status: "active", Color: "black"}
+	white := &models.Player{GameId: game.Id, UserId: opponent.Id, Status: "active", Color: "white"}
+	if err := repo.CreatePlayer(black); err != nil {
+		return nil, err
+	}
+	if err := repo.CreatePlayer(white); err != nil {
+		return nil, err
+	}
+	game.Players = []models.Player{*black, *white}
+	game.PlayerTurnId = black.Id
+
+	if err := repo.UpdateGame(game); err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// LoadSGF imports an SGF game record, replaying its moves to reconstruct
+// the resulting board, and persists it as a new game.
+func LoadSGF(p graphql.ResolveParams) (interface{}, error) {
+	repo := storage.FromContext(p.Context)
+
+	data := p.Args["data"].(string)
+
+	game, board, err := sgf.Read(data)
+	if err != nil {
+		return nil, err
+	}
+	game.Status = "finished"
+	game.Board = board
+
+	if err := repo.CreateGame(game); err != nil {
+		return nil, err
+	}
+	board.GameId = game.Id
+
+	for _, s := range board.Stones {
+		if err := repo.AddStone(game.Id, s); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range game.Moves {
+		if err := repo.AddMove(game.Id, m); err != nil {
+			return nil, err
+		}
+	}
+
+	return game, nil
+}
+
+// Game looks up a single game by id.
+func Game(p graphql.ResolveParams) (interface{}, error) {
+	return loadGame(p.Context, p.Args["id"])
+}
+
+// Pass records that the current player has passed their turn, ending the
+// game once both players have passed in succession.
+func Pass(p graphql.ResolveParams) (interface{}, error) {
+	repo := storage.FromContext(p.Context)
+
+	gameId := p.Args["gameId"]
+
+	game, err := loadGame(p.Context, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repo.SetPlayerPassed(game.PlayerTurnId, true); err != nil {
+		return nil, err
+	}
+	allPassed := len(game.Players) > 0
+	for i := range game.Players {
+		if game.Players[i].Id == game.PlayerTurnId {
+			game.Players[i].HasPassed = true
+		}
+		if !game.Players[i].HasPassed {
+			allPassed = false
+		}
+	}
+
+	move := models.Move{Pass: true}
+	game.Moves = append(game.Moves, move)
+	if err := repo.AddMove(game.Id, move); err != nil {
+		return nil, err
+	}
+
+	if next := otherPlayer(game, game.PlayerTurnId); next != nil {
+		game.PlayerTurnId = next.Id
+	}
+	if allPassed {
+		game.Status = "finished"
+	}
+	if err := repo.UpdateGame(game); err != nil {
+		return nil, err
+	}
+
+	if rules.Hub != nil {
+		rules.Hub.Publish(game.Id, game)
+		if game.Status == "finished" {
+			rules.Hub.Publish(game.Id, models.GameEndedEvent{Game: game})
+		}
+	}
+
+	return game, nil
+}
+
+// AddStone places a stone for the current player, applying capture rules
+// before persisting the resulting board.
+func AddStone(p graphql.ResolveParams) (interface{}, error) {
+	if models.UserFromContext(p.Context) == nil {
+		return nil, errors.New("must be logged in")
+	}
+
+	repo := storage.FromContext(p.Context)
+
+	input := p.Args["input"].(map[string]interface{})
+	gameId := input["gameId"]
+	x := input["x"].(int)
+	y := input["y"].(int)
+
+	game, err := loadGame(p.Context, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := validators.Validate(validators.AddStoneInput{GameId: gameId.(string), X: x, Y: y, BoardSize: game.Board.Size}); len(errs) > 0 {
+		return nil, &validators.ValidationError{FieldErrors: errs}
+	}
+	game.Board.GameId = game.Id
+
+	color := "black"
+	for i, player := range game.Players {
+		if player.Id == game.PlayerTurnId {
+			color = player.Color
+		}
+		if player.HasPassed {
+			game.Players[i].HasPassed = false
+			if err := repo.SetPlayerPassed(player.Id, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	stone := models.Stone{X: x, Y: y, Color: color}
+	game.Board.Stones = append(game.Board.Stones, stone)
+	move := models.Move{Color: color, X: x, Y: y}
+	game.Moves = append(game.Moves, move)
+
+	toRemove, err := rules.Run(game, stone)
+	if err != nil {
+		game.Board.Stones = game.Board.Stones[:len(game.Board.Stones)-1]
+		return nil, err
+	}
+
+	for _, str := range toRemove {
+		for _, removed := range str {
+			game.Board.Stones = removeStone(game.Board.Stones, removed)
+		}
+	}
+
+	if err := repo.AddStone(game.Id, stone); err != nil {
+		return nil, err
+	}
+	if err := repo.AddMove(game.Id, move); err != nil {
+		return nil, err
+	}
+	if next := otherPlayer(game, game.PlayerTurnId); next != nil {
+		game.PlayerTurnId = next.Id
+	}
+	if err := repo.UpdateGame(game); err != nil {
+		return nil, err
+	}
+
+	if rules.Hub != nil {
+		rules.Hub.Publish(game.Id, stone)
+		rules.Hub.Publish(game.Id, game)
+	}
+
+	return game, nil
+}
+
+// otherPlayer returns game's participant who isn't playerId, so a move or
+// pass can hand the turn to them, or nil if none is found (e.g. a game
+// persisted before players were tracked).
+func otherPlayer(game *models.Game, playerId int) *models.Player {
+	for i, player := range game.Players {
+		if player.Id != playerId {
+			return &game.Players[i]
+		}
+	}
+	return nil
+}
+
+func removeStone(stones []models.Stone, target models.Stone) []models.Stone {
+	updated := make([]models.Stone, 0, len(stones))
+	for _, s := range stones {
+		if s.X == target.X && s.Y == target.Y {
+			continue
+		}
+		updated = append(updated, s)
+	}
+	return updated
+}
+
+// loadGame decodes id (a Game's opaque global ID, as received from a
+// query, mutation, or subscription argument) and loads it from repo.
+func loadGame(ctx context.Context, id interface{}) (*models.Game, error) {
+	repo := storage.FromContext(ctx)
+
+	typeName, rawId, err := relay.FromGlobalID(id.(string))
+	if err != nil {
+		return nil, err
+	}
+	if typeName != "Game" {
+		return nil, fmt.Errorf("not a game id: %q", id)
+	}
+
+	localId, err := strconv.Atoi(rawId)
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := repo.GetGame(localId)
+	if err != nil {
+		return nil, err
+	}
+
+	if game.Board == nil {
+		game.Board = &models.Board{Size: 19, Stones: []models.Stone{}}
+	}
+	if len(game.PositionHistory) == 0 {
+		game.PositionHistory = []uint64{rules.PositionHash(game.Board.Stones)}
+	}
+
+	return game, nil
+}
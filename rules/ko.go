@@ -0,0 +1,18 @@
+package rules
+
+// Superko selects positional superko enforcement on models.Game.KoRule.
+// Any other value (including the zero value) falls back to simple ko,
+// which only rejects an immediate one-move recapture.
+const Superko = "superko"
+
+type koError struct{}
+
+func (e koError) Error() string {
+	return "Move violates ko"
+}
+
+type superkoError struct{}
+
+func (e superkoError) Error() string {
+	return "Move violates positional superko"
+}
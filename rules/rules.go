@@ -8,18 +8,27 @@ package rules
 import (
 	_ "fmt"
 	"github.com/camirmas/go_stop/models"
+	"github.com/camirmas/go_stop/pubsub"
 	"reflect"
 	"sort"
 )
 
+// Hub, when set, receives a "captured" event for every String removed by
+// Run. It is left nil in tests so rules stays usable without a live
+// subscription layer.
+var Hub *pubsub.Hub
+
 // A String is a chain of Stones on a Go Board. A string is defined as any
 // set of Stones for which each Stone is adjacent to at least one other Stone.
 type String []models.Stone
 
-// Run determines whether any Stones should be removed from the Board, based on
-// game rules. In the future this may also update the Board in addition to
-// returning captured Stones.
-func Run(board *models.Board, stone models.Stone) ([]String, error) {
+// Run determines whether any Stones should be removed from the Board, based
+// on game rules, and rejects the move outright if it is a self-capture or
+// recreates a prior board position (ko/superko, per game.KoRule). On
+// success it also updates game.PositionHistory and the board's running
+// capture counts.
+func Run(game *models.Game, stone models.Stone) ([]String, error) {
+	board := game.Board
 	strings := getStrings(board)
 
 	toRemove := make([]String, 0)
@@ -45,9 +54,79 @@ func Run(board *models.Board, stone models.Stone) ([]String, error) {
 		toRemove = updatedRemove
 	}
 
+	hash := PositionHash(applyRemovals(board.Stones, toRemove))
+	if isKo(game, hash) {
+		if game.KoRule == Superko {
+			return []String{}, superkoError{}
+		}
+		return []String{}, koError{}
+	}
+
+	for _, str := range toRemove {
+		if stone.Color == "black" {
+			board.BlackCaptures += len(str)
+		} else if stone.Color == "white" {
+			board.WhiteCaptures += len(str)
+		}
+	}
+
+	game.PositionHistory = append(game.PositionHistory, hash)
+
+	if Hub != nil {
+		for _, str := range toRemove {
+			Hub.Publish(game.Id, str)
+		}
+	}
+
 	return toRemove, nil
 }
 
+// applyRemovals returns the stones that remain on the board once every
+// string in toRemove has been captured.
+func applyRemovals(stones []models.Stone, toRemove []String) []models.Stone {
+	result := make([]models.Stone, 0, len(stones))
+
+	for _, s := range stones {
+		captured := false
+		for _, str := range toRemove {
+			if contains(str, s) {
+				captured = true
+				break
+			}
+		}
+		if !captured {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+// isKo reports whether hash recreates a position forbidden by game.KoRule.
+// game.PositionHistory holds the hash reached after every move played so
+// far, seeded with the starting position. Positional superko forbids
+// recreating any of them; simple ko only forbids recreating the position
+// from one move back, i.e. the second-to-last entry (the position that
+// existed immediately before the opponent's last move).
+func isKo(game *models.Game, hash uint64) bool {
+	history := game.PositionHistory
+
+	if game.KoRule == Superko {
+		for _, h := range history {
+			if h == hash {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(history) < 2 {
+		return false
+	}
+
+	return history[len(history)-2] == hash
+}
+
 func findLiberties(board *models.Board, str String) ([]models.Stone, int) {
 	liberties := make([]models.Stone, 0)
 
@@ -116,7 +195,7 @@ func getNearby(board *models.Board, stone models.Stone) []models.Stone {
 	validStones := make([]models.Stone, 0)
 
 	for _, s := range nearbyStones {
-		if isInbounds(board.Size, s) {
+		if IsInbounds(board.Size, s) {
 			existingStone := find(board.Stones, s)
 
 			if existingStone != nil {
@@ -130,7 +209,9 @@ func getNearby(board *models.Board, stone models.Stone) []models.Stone {
 	return validStones
 }
 
-func isInbounds(size int, stone models.Stone) bool {
+// IsInbounds reports whether stone's coordinates fall within a size x
+// size board.
+func IsInbounds(size int, stone models.Stone) bool {
 	if stone.X < 0 || stone.X >= size {
 		return false
 	}
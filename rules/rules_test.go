@@ -0,0 +1,169 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/camirmas/go_stop/models"
+)
+
+// newGame builds a Game whose PositionHistory is seeded from stones, the
+// same way resolvers.CreateGame and sgf.Read do before any moves are run.
+func newGame(size int, stones []models.Stone, koRule string) *models.Game {
+	board := &models.Board{Size: size, Stones: append([]models.Stone{}, stones...)}
+	return &models.Game{
+		Board:           board,
+		KoRule:          koRule,
+		PositionHistory: []uint64{PositionHash(board.Stones)},
+	}
+}
+
+func TestRun_BasicKo(t *testing.T) {
+	// . B W .
+	// B W . W
+	// . B W .
+	setup := []models.Stone{
+		{X: 1, Y: 0, Color: "black"},
+		{X: 0, Y: 1, Color: "black"},
+		{X: 1, Y: 2, Color: "black"},
+		{X: 2, Y: 0, Color: "white"},
+		{X: 1, Y: 1, Color: "white"},
+		{X: 3, Y: 1, Color: "white"},
+		{X: 2, Y: 2, Color: "white"},
+	}
+	game := newGame(4, setup, "")
+
+	// Black captures the lone white stone at (1,1).
+	capture := models.Stone{X: 2, Y: 1, Color: "black"}
+	game.Board.Stones = append(game.Board.Stones, capture)
+	toRemove, err := Run(game, capture)
+	if err != nil {
+		t.Fatalf("expected capture to succeed, got %v", err)
+	}
+	if len(toRemove) != 1 || len(toRemove[0]) != 1 {
+		t.Fatalf("expected a single stone captured, got %v", toRemove)
+	}
+	game.Board.Stones = applyRemovals(game.Board.Stones, toRemove)
+
+	// White immediately retaking (1,1) would recreate the position from
+	// before black's move: forbidden by simple ko.
+	recapture := models.Stone{X: 1, Y: 1, Color: "white"}
+	game.Board.Stones = append(game.Board.Stones, recapture)
+	if _, err := Run(game, recapture); err == nil {
+		t.Fatal("expected ko violation, got nil error")
+	}
+}
+
+func TestRun_SendingTwoReturningOne(t *testing.T) {
+	// Two white stones along the top edge share a single remaining
+	// liberty at the corner. Filling it captures both at once, which
+	// must not be mistaken for ko (it is a two-for-one trade, not a
+	// one-move recapture).
+	//   W W .
+	//   B B .
+	//   . . .
+	setup := []models.Stone{
+		{X: 0, Y: 2, Color: "white"},
+		{X: 1, Y: 2, Color: "white"},
+		{X: 0, Y: 1, Color: "black"},
+		{X: 1, Y: 1, Color: "black"},
+	}
+	game := newGame(3, setup, "")
+
+	stone := models.Stone{X: 2, Y: 2, Color: "black"}
+	game.Board.Stones = append(game.Board.Stones, stone)
+
+	toRemove, err := Run(game, stone)
+	if err != nil {
+		t.Fatalf("expected two-for-one capture to succeed, got %v", err)
+	}
+
+	captured := 0
+	for _, str := range toRemove {
+		captured += len(str)
+	}
+	if captured != 2 {
+		t.Fatalf("expected 2 stones captured, got %d", captured)
+	}
+}
+
+func TestRun_PositionalSuperko(t *testing.T) {
+	// Positional superko must also reject the immediate recapture that
+	// simple ko forbids; recreating a position further back in the history
+	// is covered by TestRun_TripleKoCycle_SuperkoRejectsWhatSimpleKoAllows.
+	setup := []models.Stone{
+		{X: 1, Y: 0, Color: "black"},
+		{X: 0, Y: 1, Color: "black"},
+		{X: 1, Y: 2, Color: "black"},
+		{X: 2, Y: 0, Color: "white"},
+		{X: 1, Y: 1, Color: "white"},
+		{X: 3, Y: 1, Color: "white"},
+		{X: 2, Y: 2, Color: "white"},
+	}
+
+	game := newGame(4, setup, Superko)
+
+	capture := models.Stone{X: 2, Y: 1, Color: "black"}
+	game.Board.Stones = append(game.Board.Stones, capture)
+	toRemove, err := Run(game, capture)
+	if err != nil {
+		t.Fatalf("expected initial capture to succeed, got %v", err)
+	}
+	game.Board.Stones = applyRemovals(game.Board.Stones, toRemove)
+
+	recapture := models.Stone{X: 1, Y: 1, Color: "white"}
+	game.Board.Stones = append(game.Board.Stones, recapture)
+	if _, err := Run(game, recapture); err == nil {
+		t.Fatal("expected superko violation on immediate recapture, got nil error")
+	}
+}
+
+func TestRun_TripleKoCycle_SuperkoRejectsWhatSimpleKoAllows(t *testing.T) {
+	// Two independent ko shapes (the same shape as TestRun_BasicKo, offset
+	// so they never interact) let black capture both in turn, then white
+	// recapture both in turn. The last recapture restores the exact
+	// starting position - four plies back, not the one ply simple ko
+	// checks - the same shape a real triple-ko cycle keeps recreating.
+	// Simple ko only forbids undoing the immediately preceding move, so it
+	// lets this recapture through; positional superko scans the whole
+	// history and must reject it.
+	setup := []models.Stone{
+		{X: 1, Y: 0, Color: "black"}, {X: 0, Y: 1, Color: "black"}, {X: 1, Y: 2, Color: "black"},
+		{X: 2, Y: 0, Color: "white"}, {X: 1, Y: 1, Color: "white"}, {X: 3, Y: 1, Color: "white"}, {X: 2, Y: 2, Color: "white"},
+
+		{X: 6, Y: 0, Color: "black"}, {X: 5, Y: 1, Color: "black"}, {X: 6, Y: 2, Color: "black"},
+		{X: 7, Y: 0, Color: "white"}, {X: 6, Y: 1, Color: "white"}, {X: 8, Y: 1, Color: "white"}, {X: 7, Y: 2, Color: "white"},
+	}
+
+	run := func(koRule string) error {
+		game := newGame(9, setup, koRule)
+
+		play := func(s models.Stone) error {
+			game.Board.Stones = append(game.Board.Stones, s)
+			toRemove, err := Run(game, s)
+			if err != nil {
+				game.Board.Stones = game.Board.Stones[:len(game.Board.Stones)-1]
+				return err
+			}
+			game.Board.Stones = applyRemovals(game.Board.Stones, toRemove)
+			return nil
+		}
+
+		if err := play(models.Stone{X: 2, Y: 1, Color: "black"}); err != nil { // capture A
+			t.Fatalf("capture A: %v", err)
+		}
+		if err := play(models.Stone{X: 7, Y: 1, Color: "black"}); err != nil { // capture B
+			t.Fatalf("capture B: %v", err)
+		}
+		if err := play(models.Stone{X: 1, Y: 1, Color: "white"}); err != nil { // recapture A
+			t.Fatalf("recapture A: %v", err)
+		}
+		return play(models.Stone{X: 6, Y: 1, Color: "white"}) // recapture B: recreates the starting position
+	}
+
+	if err := run(""); err != nil {
+		t.Fatalf("simple ko: expected the 4-ply-back recapture to be allowed, got %v", err)
+	}
+	if err := run(Superko); err == nil {
+		t.Fatal("superko: expected the 4-ply-back recapture to be rejected")
+	}
+}
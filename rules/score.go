@@ -0,0 +1,124 @@
+package rules
+
+import "github.com/camirmas/go_stop/models"
+
+// Japanese and Chinese are the two counting rules FinalScore understands.
+const (
+	Japanese = "japanese"
+	Chinese  = "chinese"
+)
+
+// Score walks every empty point on board, flood-filling connected empty
+// regions to attribute territory to whichever color exclusively borders
+// it. A region bordered by both colors (dame) counts for neither.
+// Captures are read off the board's running totals, which rules.Run
+// maintains as stones are removed during play. Automatic dead-stone
+// detection is out of scope here, so dead is always empty; a future pass
+// can let a player mark groups dead before scoring.
+func Score(board *models.Board) (blackTerritory, whiteTerritory, blackCaptures, whiteCaptures int, dead []models.Stone, err error) {
+	visited := make(map[[2]int]bool)
+
+	for y := 0; y < board.Size; y++ {
+		for x := 0; x < board.Size; x++ {
+			point := models.Stone{X: x, Y: y}
+			key := [2]int{x, y}
+
+			if visited[key] {
+				continue
+			}
+			if existing := find(board.Stones, point); existing != nil {
+				visited[key] = true
+				continue
+			}
+
+			region, borders := floodEmpty(board, point, visited)
+
+			switch {
+			case borders["black"] && !borders["white"]:
+				blackTerritory += len(region)
+			case borders["white"] && !borders["black"]:
+				whiteTerritory += len(region)
+			}
+		}
+	}
+
+	return blackTerritory, whiteTerritory, board.BlackCaptures, board.WhiteCaptures, []models.Stone{}, nil
+}
+
+// FinalScore combines territory and captures per the requested counting
+// rule: Japanese scores territory plus prisoners, Chinese scores
+// territory plus live stones remaining on the board (area scoring). komi
+// is added to white's total either way, to offset black's first-move
+// advantage.
+func FinalScore(board *models.Board, rule string, komi float64) (black, white float64, err error) {
+	blackTerritory, whiteTerritory, blackCaptures, whiteCaptures, _, err := Score(board)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch rule {
+	case Chinese:
+		blackStones, whiteStones := 0, 0
+		for _, s := range board.Stones {
+			if s.Color == "black" {
+				blackStones++
+			} else if s.Color == "white" {
+				whiteStones++
+			}
+		}
+		return float64(blackTerritory + blackStones), float64(whiteTerritory+whiteStones) + komi, nil
+	default:
+		return float64(blackTerritory + blackCaptures), float64(whiteTerritory+whiteCaptures) + komi, nil
+	}
+}
+
+// floodEmpty collects the connected region of empty points reachable from
+// start and the set of stone colors bordering it, marking every visited
+// point along the way.
+func floodEmpty(board *models.Board, start models.Stone, visited map[[2]int]bool) ([]models.Stone, map[string]bool) {
+	region := make([]models.Stone, 0)
+	borders := make(map[string]bool)
+	stack := []models.Stone{start}
+
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		key := [2]int{p.X, p.Y}
+		if visited[key] {
+			continue
+		}
+		visited[key] = true
+		region = append(region, p)
+
+		for _, n := range neighbors(board.Size, p) {
+			if existing := find(board.Stones, n); existing != nil {
+				borders[existing.Color] = true
+				continue
+			}
+			if !visited[[2]int{n.X, n.Y}] {
+				stack = append(stack, n)
+			}
+		}
+	}
+
+	return region, borders
+}
+
+func neighbors(size int, p models.Stone) []models.Stone {
+	candidates := []models.Stone{
+		{X: p.X, Y: p.Y + 1},
+		{X: p.X, Y: p.Y - 1},
+		{X: p.X - 1, Y: p.Y},
+		{X: p.X + 1, Y: p.Y},
+	}
+
+	valid := make([]models.Stone, 0, len(candidates))
+	for _, c := range candidates {
+		if IsInbounds(size, c) {
+			valid = append(valid, c)
+		}
+	}
+
+	return valid
+}
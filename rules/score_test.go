@@ -0,0 +1,125 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/camirmas/go_stop/models"
+)
+
+func TestScore_Territory(t *testing.T) {
+	// A black wall across the middle row of a 5x5 board; everything
+	// above and below it is empty and borders only black.
+	//   . . . . .
+	//   . . . . .
+	//   B B B B B
+	//   . . . . .
+	//   . . . . .
+	board := &models.Board{Size: 5}
+	for x := 0; x < 5; x++ {
+		board.Stones = append(board.Stones, models.Stone{X: x, Y: 2, Color: "black"})
+	}
+
+	blackTerritory, whiteTerritory, _, _, _, err := Score(board)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if blackTerritory != 20 {
+		t.Fatalf("got blackTerritory %d, want 20", blackTerritory)
+	}
+	if whiteTerritory != 0 {
+		t.Fatalf("got whiteTerritory %d, want 0", whiteTerritory)
+	}
+}
+
+func TestScore_DameNotCounted(t *testing.T) {
+	// An empty region bordered by both colors counts for neither.
+	//   B W
+	//   . .
+	board := &models.Board{
+		Size: 2,
+		Stones: []models.Stone{
+			{X: 0, Y: 0, Color: "black"},
+			{X: 1, Y: 0, Color: "white"},
+		},
+	}
+
+	blackTerritory, whiteTerritory, _, _, _, err := Score(board)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if blackTerritory != 0 || whiteTerritory != 0 {
+		t.Fatalf("got black=%d white=%d, want 0/0 for a dame region", blackTerritory, whiteTerritory)
+	}
+}
+
+func TestScore_CapturesReadFromBoard(t *testing.T) {
+	board := &models.Board{Size: 5, BlackCaptures: 3, WhiteCaptures: 1}
+
+	_, _, blackCaptures, whiteCaptures, dead, err := Score(board)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if blackCaptures != 3 || whiteCaptures != 1 {
+		t.Fatalf("got blackCaptures=%d whiteCaptures=%d, want 3/1", blackCaptures, whiteCaptures)
+	}
+	if len(dead) != 0 {
+		t.Fatalf("got dead=%v, want empty (dead-stone detection is not implemented)", dead)
+	}
+}
+
+func TestFinalScore_Japanese(t *testing.T) {
+	// Black's wall from TestScore_Territory, plus a black capture.
+	board := &models.Board{Size: 5, BlackCaptures: 2}
+	for x := 0; x < 5; x++ {
+		board.Stones = append(board.Stones, models.Stone{X: x, Y: 2, Color: "black"})
+	}
+
+	black, white, err := FinalScore(board, Japanese, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if black != 22 {
+		t.Fatalf("got black %v, want 22 (20 territory + 2 captures)", black)
+	}
+	if white != 0 {
+		t.Fatalf("got white %v, want 0", white)
+	}
+}
+
+func TestFinalScore_Chinese(t *testing.T) {
+	// Chinese (area) scoring counts live stones instead of captures, so
+	// the same board scores differently under each rule.
+	board := &models.Board{Size: 5, BlackCaptures: 2}
+	for x := 0; x < 5; x++ {
+		board.Stones = append(board.Stones, models.Stone{X: x, Y: 2, Color: "black"})
+	}
+
+	black, white, err := FinalScore(board, Chinese, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if black != 25 {
+		t.Fatalf("got black %v, want 25 (20 territory + 5 live stones)", black)
+	}
+	if white != 0 {
+		t.Fatalf("got white %v, want 0", white)
+	}
+}
+
+func TestFinalScore_AddsKomiToWhite(t *testing.T) {
+	board := &models.Board{Size: 5}
+	for x := 0; x < 5; x++ {
+		board.Stones = append(board.Stones, models.Stone{X: x, Y: 2, Color: "black"})
+	}
+
+	black, white, err := FinalScore(board, Japanese, 6.5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if black != 20 {
+		t.Fatalf("got black %v, want 20 (unaffected by komi)", black)
+	}
+	if white != 6.5 {
+		t.Fatalf("got white %v, want 6.5 (no territory or captures, plus komi)", white)
+	}
+}
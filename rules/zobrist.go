@@ -0,0 +1,43 @@
+package rules
+
+import (
+	"math/rand"
+
+	"github.com/camirmas/go_stop/models"
+)
+
+// MaxBoardSize bounds the precomputed Zobrist table; no supported board
+// (9x9, 13x13, 19x19) comes close to it.
+const MaxBoardSize = 25
+
+// zobristTable holds one random uint64 per (x, y, color) triple, assigned
+// once at process init and XORed together to fingerprint a board
+// position for ko detection.
+var zobristTable [MaxBoardSize][MaxBoardSize][2]uint64
+
+func init() {
+	r := rand.New(rand.NewSource(1))
+	for x := 0; x < MaxBoardSize; x++ {
+		for y := 0; y < MaxBoardSize; y++ {
+			zobristTable[x][y][0] = r.Uint64()
+			zobristTable[x][y][1] = r.Uint64()
+		}
+	}
+}
+
+func colorIndex(color string) int {
+	if color == "white" {
+		return 1
+	}
+	return 0
+}
+
+// PositionHash fingerprints a set of stones so two boards in the same
+// position always hash identically, regardless of move order.
+func PositionHash(stones []models.Stone) uint64 {
+	var hash uint64
+	for _, s := range stones {
+		hash ^= zobristTable[s.X][s.Y][colorIndex(s.Color)]
+	}
+	return hash
+}
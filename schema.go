@@ -0,0 +1,11 @@
+package main
+
+import "github.com/graphql-go/graphql"
+
+// schema is the single GraphQL schema served over both the HTTP endpoint
+// and the WebSocket subscription transport.
+var schema, _ = graphql.NewSchema(graphql.SchemaConfig{
+	Query:        queryType,
+	Mutation:     mutationType,
+	Subscription: subscriptionType,
+})
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/camirmas/go_stop/models"
+	"github.com/camirmas/go_stop/storage"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/handler"
+)
+
+// Config controls how RegisterGraphQLService exposes a schema over HTTP.
+//
+// ReadTimeout and WriteTimeout are not enforced here, since a Handler
+// doesn't own the listener that ultimately needs them; the caller should
+// set them on the *http.Server it builds around the mux passed in.
+type Config struct {
+	Repo           storage.Repository
+	CorsOrigins    []string
+	VirtualHosts   []string
+	EnableGraphiQL bool
+	ReadTimeout    int
+	WriteTimeout   int
+}
+
+// RegisterGraphQLService mounts schema on mux at /graphql (POST and GET)
+// and, when enabled, a GraphiQL playground at /graphiql. Every request is
+// checked against cfg.VirtualHosts, given a CORS preflight response per
+// cfg.CorsOrigins, and passed through JWT authentication before reaching
+// the schema, so resolvers can read the caller off the request context.
+func RegisterGraphQLService(mux *http.ServeMux, schema graphql.Schema, cfg Config) {
+	h := handler.New(&handler.Config{
+		Schema:   &schema,
+		Pretty:   true,
+		GraphiQL: false,
+	})
+
+	graphqlHandler := withVirtualHosts(cfg.VirtualHosts,
+		withCORS(cfg.CorsOrigins,
+			withRepository(cfg.Repo,
+				withAuth(h))))
+
+	mux.Handle("/graphql", graphqlHandler)
+
+	if cfg.EnableGraphiQL {
+		graphiql := handler.New(&handler.Config{
+			Schema:   &schema,
+			GraphiQL: true,
+		})
+		mux.Handle("/graphiql", withVirtualHosts(cfg.VirtualHosts, graphiql))
+	}
+}
+
+// withVirtualHosts rejects requests whose Host header doesn't match one
+// of hosts. An empty list disables the check, allowing any host.
+func withVirtualHosts(hosts []string, next http.Handler) http.Handler {
+	if len(hosts) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if i := strings.LastIndex(host, ":"); i != -1 {
+			host = host[:i]
+		}
+
+		for _, allowed := range hosts {
+			if host == allowed {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		http.Error(w, "unknown host", http.StatusForbidden)
+	})
+}
+
+// withCORS answers preflight requests and sets the response headers
+// needed for browsers on origins to call the API. An empty list disables
+// CORS handling entirely.
+func withCORS(origins []string, next http.Handler) http.Handler {
+	if len(origins) == 0 {
+		return next
+	}
+
+	allowed := make(map[string]bool, len(origins))
+	for _, o := range origins {
+		allowed[o] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if allowed[origin] || allowed["*"] {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		}
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withRepository injects repo into every request's context so resolvers
+// can reach it via storage.FromContext.
+func withRepository(repo storage.Repository, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(storage.NewContext(r.Context(), repo)))
+	})
+}
+
+// withAuth parses a `Bearer` JWT off the Authorization header, if
+// present, and injects the *models.User it identifies into the request
+// context. A missing or invalid token is not an error here: resolvers
+// that require auth check models.UserFromContext themselves.
+func withAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := r.Header.Get("Authorization")
+		prefix := "Bearer "
+		if !strings.HasPrefix(raw, prefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, err := jwt.Parse(strings.TrimPrefix(raw, prefix), func(t *jwt.Token) (interface{}, error) {
+			return []byte(os.Getenv("JWT_SECRET")), nil
+		})
+		if err != nil || !token.Valid {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		sub, ok := claims["sub"].(float64)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		repo := storage.FromContext(r.Context())
+		user, err := repo.GetUserById(int(sub))
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(models.NewContext(r.Context(), user)))
+	})
+}
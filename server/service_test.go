@@ -0,0 +1,105 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/camirmas/go_stop/models"
+)
+
+func TestWithVirtualHosts_RejectsUnknownHost(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	h := withVirtualHosts([]string{"api.example.com"}, next)
+
+	req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	req.Host = "evil.example.com"
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestWithVirtualHosts_AllowsKnownHost(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	h := withVirtualHosts([]string{"api.example.com"}, next)
+
+	req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	req.Host = "api.example.com"
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestWithCORS_PreflightFromAllowedOrigin(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	h := withCORS([]string{"https://app.example.com"}, next)
+
+	req := httptest.NewRequest(http.MethodOptions, "/graphql", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("got Access-Control-Allow-Origin %q, want the requesting origin", got)
+	}
+}
+
+func TestWithCORS_RejectsDisallowedOrigin(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	h := withCORS([]string{"https://app.example.com"}, next)
+
+	req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("got Access-Control-Allow-Origin %q, want empty", got)
+	}
+}
+
+func TestWithAuth_AnonymousRequestPassesThrough(t *testing.T) {
+	var user *models.User
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user = models.UserFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	rec := httptest.NewRecorder()
+
+	withAuth(next).ServeHTTP(rec, req)
+
+	if user != nil {
+		t.Fatalf("got user %+v, want nil for an unauthenticated request", user)
+	}
+}
+
+func TestWithAuth_InvalidTokenPassesThroughUnauthenticated(t *testing.T) {
+	var user *models.User
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user = models.UserFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	req.Header.Set("Authorization", "Bearer not-a-real-token")
+	rec := httptest.NewRecorder()
+
+	withAuth(next).ServeHTTP(rec, req)
+
+	if user != nil {
+		t.Fatalf("got user %+v, want nil for an invalid token", user)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Package server hosts the HTTP and WebSocket transports for the GraphQL
+schema defined in the main package.
+*/
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/gorilla/websocket"
+)
+
+// GraphQLWSSubprotocol is the subprotocol name negotiated for
+// subscription connections, per https://github.com/enisdenjo/graphql-ws.
+const GraphQLWSSubprotocol = "graphql-transport-ws"
+
+var upgrader = websocket.Upgrader{
+	Subprotocols: []string{GraphQLWSSubprotocol},
+	CheckOrigin:  func(r *http.Request) bool { return true },
+}
+
+type wsMessage struct {
+	Id      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type subscribePayload struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// SubscriptionHandler upgrades HTTP requests carrying the
+// graphql-transport-ws subprotocol and drives the connection's message
+// loop against schema.
+func SubscriptionHandler(schema graphql.Schema) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		for {
+			var msg wsMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+
+			switch msg.Type {
+			case "connection_init":
+				conn.WriteJSON(wsMessage{Type: "connection_ack"})
+			case "subscribe":
+				go handleSubscribe(ctx, conn, schema, msg)
+			case "complete":
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func handleSubscribe(ctx context.Context, conn *websocket.Conn, schema graphql.Schema, msg wsMessage) {
+	var payload subscribePayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		writeError(conn, msg.Id, err)
+		return
+	}
+
+	results := graphql.Subscribe(graphql.Params{
+		Schema:         schema,
+		RequestString:  payload.Query,
+		VariableValues: payload.Variables,
+		Context:        ctx,
+	})
+
+	for result := range results {
+		if len(result.Errors) > 0 {
+			writeError(conn, msg.Id, result.Errors[0])
+			return
+		}
+
+		conn.WriteJSON(wsMessage{
+			Id:      msg.Id,
+			Type:    "next",
+			Payload: mustMarshal(map[string]interface{}{"data": result.Data}),
+		})
+	}
+
+	conn.WriteJSON(wsMessage{Id: msg.Id, Type: "complete"})
+}
+
+func writeError(conn *websocket.Conn, id string, err error) {
+	conn.WriteJSON(wsMessage{
+		Id:      id,
+		Type:    "error",
+		Payload: mustMarshal([]map[string]interface{}{{"message": err.Error()}}),
+	})
+}
+
+func mustMarshal(v interface{}) json.RawMessage {
+	b, _ := json.Marshal(v)
+	return b
+}
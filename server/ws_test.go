@@ -0,0 +1,97 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/gorilla/websocket"
+)
+
+// tickSchema is a minimal schema whose one subscription field streams a
+// single pre-loaded event, enough to prove SubscriptionHandler actually
+// drives a subscription end-to-end instead of running it as a query.
+func tickSchema(t *testing.T) graphql.Schema {
+	t.Helper()
+
+	tickField := &graphql.Field{
+		Type: graphql.Int,
+		Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+			out := make(chan interface{}, 1)
+			out <- 42
+			close(out)
+			return out, nil
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source, nil
+		},
+	}
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Query",
+			Fields: graphql.Fields{"tick": tickField},
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Subscription",
+			Fields: graphql.Fields{"tick": tickField},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("building test schema: %v", err)
+	}
+	return schema
+}
+
+func TestSubscriptionHandler_StreamsNextFrame(t *testing.T) {
+	server := httptest.NewServer(SubscriptionHandler(tickSchema(t)))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	dialer := websocket.Dialer{Subprotocols: []string{GraphQLWSSubprotocol}}
+	conn, _, err := dialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dialing subscription server: %v", err)
+	}
+	defer conn.Close()
+
+	if err := conn.WriteJSON(wsMessage{Type: "connection_init"}); err != nil {
+		t.Fatalf("writing connection_init: %v", err)
+	}
+	var ack wsMessage
+	if err := conn.ReadJSON(&ack); err != nil {
+		t.Fatalf("reading connection_ack: %v", err)
+	}
+	if ack.Type != "connection_ack" {
+		t.Fatalf("got message type %q, want connection_ack", ack.Type)
+	}
+
+	err = conn.WriteJSON(wsMessage{
+		Id:      "1",
+		Type:    "subscribe",
+		Payload: mustMarshal(subscribePayload{Query: "subscription { tick }"}),
+	})
+	if err != nil {
+		t.Fatalf("writing subscribe: %v", err)
+	}
+
+	var next wsMessage
+	if err := conn.ReadJSON(&next); err != nil {
+		t.Fatalf("reading next frame: %v", err)
+	}
+	if next.Type != "next" {
+		t.Fatalf("got message type %q, want next", next.Type)
+	}
+	if !strings.Contains(string(next.Payload), `"tick":42`) {
+		t.Fatalf("got payload %s, want it to contain tick:42", next.Payload)
+	}
+
+	var complete wsMessage
+	if err := conn.ReadJSON(&complete); err != nil {
+		t.Fatalf("reading complete frame: %v", err)
+	}
+	if complete.Type != "complete" {
+		t.Fatalf("got message type %q, want complete", complete.Type)
+	}
+}
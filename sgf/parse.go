@@ -0,0 +1,40 @@
+package sgf
+
+import "regexp"
+
+// propPattern matches a single SGF property identifier followed by one
+// or more bracketed values, e.g. SZ[19] or AB[aa][bb].
+var propPattern = regexp.MustCompile(`([A-Z]+)((?:\[[^\]]*\])+)`)
+var valuePattern = regexp.MustCompile(`\[([^\]]*)\]`)
+
+// parseProps collects every property in data into a map of identifier to
+// its list of values, e.g. {"SZ": ["19"], "AB": ["aa", "bb"]}.
+func parseProps(data string) map[string][]string {
+	props := make(map[string][]string)
+
+	for _, match := range propPattern.FindAllStringSubmatch(data, -1) {
+		ident, rawValues := match[1], match[2]
+		for _, v := range valuePattern.FindAllStringSubmatch(rawValues, -1) {
+			props[ident] = append(props[ident], v[1])
+		}
+	}
+
+	return props
+}
+
+type moveNode struct {
+	tag   string
+	coord string
+}
+
+// parseMoveNodes extracts B[xy]/W[xy] nodes from data in document order.
+func parseMoveNodes(data string) []moveNode {
+	pattern := regexp.MustCompile(`;([BW])\[([^\]]*)\]`)
+
+	var nodes []moveNode
+	for _, match := range pattern.FindAllStringSubmatch(data, -1) {
+		nodes = append(nodes, moveNode{tag: match[1], coord: match[2]})
+	}
+
+	return nodes
+}
@@ -0,0 +1,176 @@
+/*
+Package sgf converts between a models.Game's move history and SGF
+(Smart Game Format) FF[4] text, as used by every other Go client and
+archive. Coordinates are encoded as the standard SGF letter pairs
+("aa" is the top-left point), which caps supported board sizes at 26.
+*/
+package sgf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/camirmas/go_stop/models"
+	"github.com/camirmas/go_stop/rules"
+)
+
+// Write serializes game's move history as an SGF game tree.
+func Write(game *models.Game) (string, error) {
+	if game.Board.Size > 26 {
+		return "", fmt.Errorf("sgf: board size %d exceeds the 26x26 SGF coordinate limit", game.Board.Size)
+	}
+
+	var b strings.Builder
+
+	b.WriteString("(;FF[4]GM[1]")
+	fmt.Fprintf(&b, "SZ[%d]", game.Board.Size)
+	fmt.Fprintf(&b, "KM[%s]", strconv.FormatFloat(game.Komi, 'f', -1, 64))
+
+	black, white := playerNames(game)
+	if black != "" {
+		fmt.Fprintf(&b, "PB[%s]", escape(black))
+	}
+	if white != "" {
+		fmt.Fprintf(&b, "PW[%s]", escape(white))
+	}
+
+	for _, move := range game.Moves {
+		tag := "B"
+		if move.Color == "white" {
+			tag = "W"
+		}
+
+		if move.Pass {
+			fmt.Fprintf(&b, ";%s[]", tag)
+			continue
+		}
+
+		fmt.Fprintf(&b, ";%s[%s]", tag, encodeCoord(move.X, move.Y))
+	}
+
+	b.WriteString(")")
+
+	return b.String(), nil
+}
+
+// Read parses SGF data, replaying every move through rules.Run so the
+// returned Board reflects captures exactly as they happened during play.
+func Read(data string) (*models.Game, *models.Board, error) {
+	props := parseProps(data)
+
+	size := 19
+	if sz, ok := props["SZ"]; ok {
+		if n, err := strconv.Atoi(sz[0]); err == nil {
+			size = n
+		}
+	}
+
+	game := &models.Game{
+		Status: "active",
+		Board:  &models.Board{Size: size, Stones: []models.Stone{}},
+	}
+
+	if km, ok := props["KM"]; ok {
+		if f, err := strconv.ParseFloat(km[0], 64); err == nil {
+			game.Komi = f
+		}
+	}
+
+	for _, coord := range props["AB"] {
+		x, y, err := decodeCoord(coord, size)
+		if err != nil {
+			return nil, nil, err
+		}
+		game.Board.Stones = append(game.Board.Stones, models.Stone{X: x, Y: y, Color: "black"})
+	}
+	for _, coord := range props["AW"] {
+		x, y, err := decodeCoord(coord, size)
+		if err != nil {
+			return nil, nil, err
+		}
+		game.Board.Stones = append(game.Board.Stones, models.Stone{X: x, Y: y, Color: "white"})
+	}
+
+	game.PositionHistory = []uint64{rules.PositionHash(game.Board.Stones)}
+
+	for _, node := range parseMoveNodes(data) {
+		color := "black"
+		if node.tag == "W" {
+			color = "white"
+		}
+
+		if node.coord == "" {
+			game.Moves = append(game.Moves, models.Move{Color: color, Pass: true})
+			continue
+		}
+
+		x, y, err := decodeCoord(node.coord, size)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		stone := models.Stone{X: x, Y: y, Color: color}
+		game.Board.Stones = append(game.Board.Stones, stone)
+
+		toRemove, err := rules.Run(game, stone)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, str := range toRemove {
+			for _, removed := range str {
+				game.Board.Stones = removeStone(game.Board.Stones, removed)
+			}
+		}
+
+		game.Moves = append(game.Moves, models.Move{Color: color, X: x, Y: y})
+	}
+
+	return game, game.Board, nil
+}
+
+func playerNames(game *models.Game) (black, white string) {
+	for _, p := range game.Players {
+		if p.User == nil {
+			continue
+		}
+		if p.Color == "black" {
+			black = p.User.Username
+		} else if p.Color == "white" {
+			white = p.User.Username
+		}
+	}
+	return black, white
+}
+
+func removeStone(stones []models.Stone, target models.Stone) []models.Stone {
+	updated := make([]models.Stone, 0, len(stones))
+	for _, s := range stones {
+		if s.X == target.X && s.Y == target.Y {
+			continue
+		}
+		updated = append(updated, s)
+	}
+	return updated
+}
+
+func encodeCoord(x, y int) string {
+	return string(rune('a'+x)) + string(rune('a'+y))
+}
+
+func decodeCoord(coord string, size int) (x, y int, err error) {
+	if len(coord) != 2 {
+		return 0, 0, fmt.Errorf("sgf: malformed coordinate %q", coord)
+	}
+
+	x, y = int(coord[0]-'a'), int(coord[1]-'a')
+	if !rules.IsInbounds(size, models.Stone{X: x, Y: y}) {
+		return 0, 0, fmt.Errorf("sgf: coordinate %q out of bounds for a %dx%d board", coord, size, size)
+	}
+	return x, y, nil
+}
+
+func escape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `]`, `\]`)
+}
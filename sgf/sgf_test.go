@@ -0,0 +1,104 @@
+package sgf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/camirmas/go_stop/models"
+)
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	game := &models.Game{
+		Board: &models.Board{Size: 9, Stones: []models.Stone{}},
+		Komi:  6.5,
+		Moves: []models.Move{
+			{Color: "black", X: 2, Y: 2},
+			{Color: "white", X: 3, Y: 3},
+			{Color: "black", Pass: true},
+		},
+	}
+	game.PositionHistory = []uint64{}
+
+	data, err := Write(game)
+	if err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	got, board, err := Read(data)
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+
+	if board.Size != 9 {
+		t.Fatalf("got board size %d, want 9", board.Size)
+	}
+	if got.Komi != 6.5 {
+		t.Fatalf("got komi %v, want 6.5", got.Komi)
+	}
+	if len(got.Moves) != len(game.Moves) {
+		t.Fatalf("got %d moves, want %d", len(got.Moves), len(game.Moves))
+	}
+	for i, move := range game.Moves {
+		if got.Moves[i] != move {
+			t.Fatalf("move %d: got %+v, want %+v", i, got.Moves[i], move)
+		}
+	}
+}
+
+func TestRead_ReplaysCaptures(t *testing.T) {
+	// A lone white stone at (1,1) on a 3x3 board, surrounded by black on
+	// three sides via setup stones, with the fourth played as a move:
+	// replaying it through rules.Run should remove the white stone from
+	// the final board, exactly as it would during live play.
+	sgfData := "(;FF[4]GM[1]SZ[3]AB[ba][ab][bc]AW[bb];B[cb])"
+
+	_, board, err := Read(sgfData)
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+
+	for _, s := range board.Stones {
+		if s.X == 1 && s.Y == 1 {
+			t.Fatalf("expected surrounded white stone at (1,1) to be captured, board: %+v", board.Stones)
+		}
+	}
+}
+
+func TestWrite_BoardTooLarge(t *testing.T) {
+	game := &models.Game{Board: &models.Board{Size: 27}}
+
+	if _, err := Write(game); err == nil {
+		t.Fatal("expected an error for a board size beyond the SGF coordinate limit, got nil")
+	}
+}
+
+func TestRead_MalformedCoordinate(t *testing.T) {
+	if _, _, err := Read("(;FF[4]GM[1]SZ[9]AB[zzz])"); err == nil {
+		t.Fatal("expected an error for a malformed coordinate, got nil")
+	}
+}
+
+func TestRead_CoordinateOutOfBounds(t *testing.T) {
+	// "z" decodes to 25, well outside a 9x9 board.
+	if _, _, err := Read("(;FF[4]GM[1]SZ[9]AB[zz])"); err == nil {
+		t.Fatal("expected an error for an out-of-bounds coordinate, got nil")
+	}
+}
+
+func TestWrite_IncludesPlayerNames(t *testing.T) {
+	game := &models.Game{
+		Board: &models.Board{Size: 9, Stones: []models.Stone{}},
+		Players: []models.Player{
+			{Color: "black", User: &models.User{Username: "hikaru"}},
+			{Color: "white", User: &models.User{Username: "akira"}},
+		},
+	}
+
+	data, err := Write(game)
+	if err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if !strings.Contains(data, "PB[hikaru]") || !strings.Contains(data, "PW[akira]") {
+		t.Fatalf("expected player names in SGF output, got %q", data)
+	}
+}
@@ -0,0 +1,323 @@
+/*
+Package mongo implements storage.Repository against MongoDB. A game's
+stones and players are embedded directly in its document rather than
+living in join tables, since Mongo has no equivalent and a game is
+always read and written as a whole.
+*/
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/camirmas/go_stop/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Store is a storage.Repository backed by MongoDB.
+type Store struct {
+	users    *mongo.Collection
+	games    *mongo.Collection
+	counters *mongo.Collection
+}
+
+// New opens the users/games collections on db and ensures the unique
+// indexes CreateUser relies on to reject duplicate accounts.
+func New(ctx context.Context, db *mongo.Database) (*Store, error) {
+	users := db.Collection("users")
+
+	_, err := users.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{
+		users:    users,
+		games:    db.Collection("games"),
+		counters: db.Collection("counters"),
+	}, nil
+}
+
+type userDoc struct {
+	Id       int    `bson:"_id"`
+	Username string `bson:"username"`
+	Email    string `bson:"email"`
+	Password string `bson:"password"`
+}
+
+type stoneDoc struct {
+	X     int    `bson:"x"`
+	Y     int    `bson:"y"`
+	Color string `bson:"color"`
+}
+
+type playerDoc struct {
+	Id        int    `bson:"id"`
+	UserId    int    `bson:"userId"`
+	Status    string `bson:"status"`
+	Color     string `bson:"color"`
+	HasPassed bool   `bson:"hasPassed"`
+}
+
+type moveDoc struct {
+	Color string `bson:"color"`
+	X     int    `bson:"x"`
+	Y     int    `bson:"y"`
+	Pass  bool   `bson:"pass"`
+}
+
+type gameDoc struct {
+	Id              int         `bson:"_id"`
+	Status          string      `bson:"status"`
+	PlayerTurnId    int         `bson:"playerTurnId"`
+	BoardSize       int         `bson:"boardSize"`
+	Komi            float64     `bson:"komi"`
+	KoRule          string      `bson:"koRule"`
+	PositionHistory []int64     `bson:"positionHistory"`
+	BlackCaptures   int         `bson:"blackCaptures"`
+	WhiteCaptures   int         `bson:"whiteCaptures"`
+	Stones          []stoneDoc  `bson:"stones"`
+	Moves           []moveDoc   `bson:"moves"`
+	Players         []playerDoc `bson:"players"`
+}
+
+// nextId atomically increments and returns the named sequence, giving us
+// the auto-incrementing integer ids models.* already exposes over
+// GraphQL without switching every id to an ObjectID.
+func (s *Store) nextId(ctx context.Context, sequence string) (int, error) {
+	var doc struct {
+		Value int `bson:"value"`
+	}
+
+	err := s.counters.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": sequence},
+		bson.M{"$inc": bson.M{"value": 1}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&doc)
+
+	return doc.Value, err
+}
+
+func (s *Store) CreateUser(user *models.User) error {
+	ctx := context.Background()
+
+	id, err := s.nextId(ctx, "users")
+	if err != nil {
+		return err
+	}
+	user.Id = id
+
+	_, err = s.users.InsertOne(ctx, userDoc{
+		Id:       user.Id,
+		Username: user.Username,
+		Email:    user.Email,
+		Password: user.Password,
+	})
+	return err
+}
+
+func (s *Store) GetUserByUsername(username string) (*models.User, error) {
+	var doc userDoc
+	if err := s.users.FindOne(context.Background(), bson.M{"username": username}).Decode(&doc); err != nil {
+		return nil, errors.New("user not found")
+	}
+	return &models.User{Id: doc.Id, Username: doc.Username, Email: doc.Email, Password: doc.Password}, nil
+}
+
+func (s *Store) GetUserById(id int) (*models.User, error) {
+	var doc userDoc
+	if err := s.users.FindOne(context.Background(), bson.M{"_id": id}).Decode(&doc); err != nil {
+		return nil, errors.New("user not found")
+	}
+	return &models.User{Id: doc.Id, Username: doc.Username, Email: doc.Email}, nil
+}
+
+func (s *Store) CreateGame(game *models.Game) error {
+	ctx := context.Background()
+
+	id, err := s.nextId(ctx, "games")
+	if err != nil {
+		return err
+	}
+	game.Id = id
+
+	_, err = s.games.InsertOne(ctx, gameDoc{
+		Id:              game.Id,
+		Status:          game.Status,
+		BoardSize:       game.Board.Size,
+		Komi:            game.Komi,
+		KoRule:          game.KoRule,
+		PositionHistory: toInt64s(game.PositionHistory),
+	})
+	return err
+}
+
+func (s *Store) GetGame(id int) (*models.Game, error) {
+	var doc gameDoc
+	if err := s.games.FindOne(context.Background(), bson.M{"_id": id}).Decode(&doc); err != nil {
+		return nil, errors.New("game not found")
+	}
+	return docToGame(doc), nil
+}
+
+func (s *Store) UpdateGame(game *models.Game) error {
+	_, err := s.games.UpdateOne(
+		context.Background(),
+		bson.M{"_id": game.Id},
+		bson.M{"$set": bson.M{
+			"status":          game.Status,
+			"playerTurnId":    game.PlayerTurnId,
+			"positionHistory": toInt64s(game.PositionHistory),
+			"blackCaptures":   game.Board.BlackCaptures,
+			"whiteCaptures":   game.Board.WhiteCaptures,
+		}},
+	)
+	return err
+}
+
+func (s *Store) AddStone(gameId int, stone models.Stone) error {
+	_, err := s.games.UpdateOne(
+		context.Background(),
+		bson.M{"_id": gameId},
+		bson.M{"$push": bson.M{"stones": stoneDoc{X: stone.X, Y: stone.Y, Color: stone.Color}}},
+	)
+	return err
+}
+
+func (s *Store) AddMove(gameId int, move models.Move) error {
+	_, err := s.games.UpdateOne(
+		context.Background(),
+		bson.M{"_id": gameId},
+		bson.M{"$push": bson.M{"moves": moveDoc{Color: move.Color, X: move.X, Y: move.Y, Pass: move.Pass}}},
+	)
+	return err
+}
+
+func (s *Store) CreatePlayer(player *models.Player) error {
+	ctx := context.Background()
+
+	id, err := s.nextId(ctx, "players")
+	if err != nil {
+		return err
+	}
+	player.Id = id
+
+	_, err = s.games.UpdateOne(
+		ctx,
+		bson.M{"_id": player.GameId},
+		bson.M{"$push": bson.M{"players": playerDoc{
+			Id:     player.Id,
+			UserId: player.UserId,
+			Status: player.Status,
+			Color:  player.Color,
+		}}},
+	)
+	return err
+}
+
+func (s *Store) SetPlayerPassed(playerId int, passed bool) error {
+	_, err := s.games.UpdateOne(
+		context.Background(),
+		bson.M{"players.id": playerId},
+		bson.M{"$set": bson.M{"players.$.hasPassed": passed}},
+	)
+	return err
+}
+
+func (s *Store) GetPlayer(id int) (models.Player, error) {
+	var doc gameDoc
+	err := s.games.FindOne(context.Background(), bson.M{"players.id": id}).Decode(&doc)
+	if err != nil {
+		return models.Player{}, errors.New("player not found")
+	}
+
+	for _, p := range doc.Players {
+		if p.Id == id {
+			return models.Player{Id: p.Id, GameId: doc.Id, UserId: p.UserId, Status: p.Status, Color: p.Color, HasPassed: p.HasPassed}, nil
+		}
+	}
+
+	return models.Player{}, errors.New("player not found")
+}
+
+func (s *Store) ListUserGames(userId, afterId, limit int) ([]*models.Game, error) {
+	cursor, err := s.games.Find(
+		context.Background(),
+		bson.M{"players.userId": userId, "_id": bson.M{"$gt": afterId}},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var games []*models.Game
+	for cursor.Next(context.Background()) {
+		var doc gameDoc
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		games = append(games, docToGame(doc))
+	}
+
+	return games, cursor.Err()
+}
+
+func docToGame(doc gameDoc) *models.Game {
+	board := &models.Board{
+		Size:          doc.BoardSize,
+		Stones:        make([]models.Stone, len(doc.Stones)),
+		BlackCaptures: doc.BlackCaptures,
+		WhiteCaptures: doc.WhiteCaptures,
+	}
+	for i, s := range doc.Stones {
+		board.Stones[i] = models.Stone{X: s.X, Y: s.Y, Color: s.Color}
+	}
+
+	players := make([]models.Player, len(doc.Players))
+	for i, p := range doc.Players {
+		players[i] = models.Player{Id: p.Id, GameId: doc.Id, UserId: p.UserId, Status: p.Status, Color: p.Color, HasPassed: p.HasPassed}
+	}
+
+	moves := make([]models.Move, len(doc.Moves))
+	for i, m := range doc.Moves {
+		moves[i] = models.Move{Color: m.Color, X: m.X, Y: m.Y, Pass: m.Pass}
+	}
+
+	return &models.Game{
+		Id:              doc.Id,
+		Status:          doc.Status,
+		PlayerTurnId:    doc.PlayerTurnId,
+		Board:           board,
+		Players:         players,
+		Moves:           moves,
+		Komi:            doc.Komi,
+		KoRule:          doc.KoRule,
+		PositionHistory: fromInt64s(doc.PositionHistory),
+	}
+}
+
+// toInt64s/fromInt64s convert a PositionHistory between the uint64 hashes
+// rules.PositionHash produces and the int64s BSON supports natively,
+// reinterpreting bits rather than truncating range.
+func toInt64s(hashes []uint64) []int64 {
+	out := make([]int64, len(hashes))
+	for i, h := range hashes {
+		out[i] = int64(h)
+	}
+	return out
+}
+
+func fromInt64s(values []int64) []uint64 {
+	out := make([]uint64, len(values))
+	for i, v := range values {
+		out[i] = uint64(v)
+	}
+	return out
+}
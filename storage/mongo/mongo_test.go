@@ -0,0 +1,52 @@
+package mongo
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/camirmas/go_stop/models"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TestStore_CreateAndGetUser is an integration test against a live
+// MongoDB instance (e.g. a mongo test container). It is skipped unless
+// MONGO_TEST_URL is set, since this sandbox has no Mongo available.
+func TestStore_CreateAndGetUser(t *testing.T) {
+	url := os.Getenv("MONGO_TEST_URL")
+	if url == "" {
+		t.Skip("MONGO_TEST_URL not set; skipping mongo integration test")
+	}
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(url))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	db := client.Database("go_stop_test")
+	defer db.Drop(ctx)
+
+	store, err := New(ctx, db)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	user := &models.User{Username: "hikaru", Email: "hikaru@example.com", Password: "hashed"}
+	if err := store.CreateUser(user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if user.Id == 0 {
+		t.Fatal("expected CreateUser to assign an id")
+	}
+
+	found, err := store.GetUserByUsername("hikaru")
+	if err != nil {
+		t.Fatalf("GetUserByUsername: %v", err)
+	}
+	if found.Id != user.Id || found.Email != user.Email {
+		t.Fatalf("got %+v, want %+v", found, user)
+	}
+}
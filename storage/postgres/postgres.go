@@ -0,0 +1,265 @@
+/*
+Package postgres implements storage.Repository against the existing
+Postgres schema, unchanged from what the resolvers used to query
+directly through models.DB.
+*/
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/camirmas/go_stop/models"
+)
+
+// Store is a storage.Repository backed by a Postgres connection pool.
+type Store struct {
+	db *sql.DB
+}
+
+// New wraps db as a storage.Repository.
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) CreateUser(user *models.User) error {
+	row := s.db.QueryRow(
+		`INSERT INTO users (username, email, password) VALUES ($1, $2, $3) RETURNING id`,
+		user.Username, user.Email, user.Password,
+	)
+	return row.Scan(&user.Id)
+}
+
+func (s *Store) GetUserByUsername(username string) (*models.User, error) {
+	user := &models.User{}
+	row := s.db.QueryRow(`SELECT id, username, email, password FROM users WHERE username = $1`, username)
+	if err := row.Scan(&user.Id, &user.Username, &user.Email, &user.Password); err != nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+func (s *Store) GetUserById(id int) (*models.User, error) {
+	user := &models.User{}
+	row := s.db.QueryRow(`SELECT id, username, email FROM users WHERE id = $1`, id)
+	if err := row.Scan(&user.Id, &user.Username, &user.Email); err != nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+func (s *Store) CreateGame(game *models.Game) error {
+	row := s.db.QueryRow(
+		`INSERT INTO games (status, board_size, komi, ko_rule, position_history, black_captures, white_captures)
+		 VALUES ($1, $2, $3, $4, $5, 0, 0) RETURNING id`,
+		game.Status, game.Board.Size, game.Komi, game.KoRule, serializeHashes(game.PositionHistory),
+	)
+	return row.Scan(&game.Id)
+}
+
+func (s *Store) GetGame(id int) (*models.Game, error) {
+	var boardSize, blackCaptures, whiteCaptures int
+	var positionHistory string
+	game := &models.Game{}
+
+	row := s.db.QueryRow(
+		`SELECT id, status, player_turn_id, board_size, komi, ko_rule, position_history, black_captures, white_captures
+		 FROM games WHERE id = $1`,
+		id,
+	)
+	if err := row.Scan(
+		&game.Id, &game.Status, &game.PlayerTurnId, &boardSize, &game.Komi, &game.KoRule,
+		&positionHistory, &blackCaptures, &whiteCaptures,
+	); err != nil {
+		return nil, errors.New("game not found")
+	}
+
+	stones, err := s.getStones(id)
+	if err != nil {
+		return nil, err
+	}
+	game.Board = &models.Board{
+		Size:          boardSize,
+		Stones:        stones,
+		BlackCaptures: blackCaptures,
+		WhiteCaptures: whiteCaptures,
+	}
+
+	history, err := parseHashes(positionHistory)
+	if err != nil {
+		return nil, err
+	}
+	game.PositionHistory = history
+
+	moves, err := s.getMoves(id)
+	if err != nil {
+		return nil, err
+	}
+	game.Moves = moves
+
+	players, err := s.getPlayers(id)
+	if err != nil {
+		return nil, err
+	}
+	game.Players = players
+
+	return game, nil
+}
+
+func (s *Store) getStones(gameId int) ([]models.Stone, error) {
+	rows, err := s.db.Query(`SELECT x, y, color FROM stones WHERE game_id = $1 ORDER BY id`, gameId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stones := []models.Stone{}
+	for rows.Next() {
+		var stone models.Stone
+		if err := rows.Scan(&stone.X, &stone.Y, &stone.Color); err != nil {
+			return nil, err
+		}
+		stones = append(stones, stone)
+	}
+	return stones, rows.Err()
+}
+
+func (s *Store) getMoves(gameId int) ([]models.Move, error) {
+	rows, err := s.db.Query(`SELECT color, x, y, pass FROM moves WHERE game_id = $1 ORDER BY id`, gameId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var moves []models.Move
+	for rows.Next() {
+		var move models.Move
+		if err := rows.Scan(&move.Color, &move.X, &move.Y, &move.Pass); err != nil {
+			return nil, err
+		}
+		moves = append(moves, move)
+	}
+	return moves, rows.Err()
+}
+
+func (s *Store) getPlayers(gameId int) ([]models.Player, error) {
+	rows, err := s.db.Query(`SELECT id, user_id, status, color, has_passed FROM players WHERE game_id = $1 ORDER BY id`, gameId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var players []models.Player
+	for rows.Next() {
+		player := models.Player{GameId: gameId}
+		if err := rows.Scan(&player.Id, &player.UserId, &player.Status, &player.Color, &player.HasPassed); err != nil {
+			return nil, err
+		}
+		players = append(players, player)
+	}
+	return players, rows.Err()
+}
+
+func (s *Store) UpdateGame(game *models.Game) error {
+	_, err := s.db.Exec(
+		`UPDATE games
+		 SET status = $1, player_turn_id = $2, position_history = $3, black_captures = $4, white_captures = $5
+		 WHERE id = $6`,
+		game.Status, game.PlayerTurnId, serializeHashes(game.PositionHistory),
+		game.Board.BlackCaptures, game.Board.WhiteCaptures, game.Id,
+	)
+	return err
+}
+
+func (s *Store) AddStone(gameId int, stone models.Stone) error {
+	_, err := s.db.Exec(
+		`INSERT INTO stones (game_id, x, y, color) VALUES ($1, $2, $3, $4)`,
+		gameId, stone.X, stone.Y, stone.Color,
+	)
+	return err
+}
+
+func (s *Store) AddMove(gameId int, move models.Move) error {
+	_, err := s.db.Exec(
+		`INSERT INTO moves (game_id, color, x, y, pass) VALUES ($1, $2, $3, $4, $5)`,
+		gameId, move.Color, move.X, move.Y, move.Pass,
+	)
+	return err
+}
+
+// serializeHashes encodes a position history as a comma-separated list of
+// hashes, so it fits in a single TEXT column alongside the rest of a
+// game's row.
+func serializeHashes(hashes []uint64) string {
+	parts := make([]string, len(hashes))
+	for i, h := range hashes {
+		parts[i] = strconv.FormatUint(h, 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseHashes(raw string) ([]uint64, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(raw, ",")
+	hashes := make([]uint64, len(parts))
+	for i, p := range parts {
+		h, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		hashes[i] = h
+	}
+	return hashes, nil
+}
+
+func (s *Store) SetPlayerPassed(playerId int, passed bool) error {
+	_, err := s.db.Exec(`UPDATE players SET has_passed = $1 WHERE id = $2`, passed, playerId)
+	return err
+}
+
+func (s *Store) CreatePlayer(player *models.Player) error {
+	row := s.db.QueryRow(
+		`INSERT INTO players (game_id, user_id, status, color, has_passed) VALUES ($1, $2, $3, $4, false) RETURNING id`,
+		player.GameId, player.UserId, player.Status, player.Color,
+	)
+	return row.Scan(&player.Id)
+}
+
+func (s *Store) GetPlayer(id int) (models.Player, error) {
+	player := models.Player{}
+	row := s.db.QueryRow(`SELECT id, game_id, user_id, status, color, has_passed FROM players WHERE id = $1`, id)
+	if err := row.Scan(&player.Id, &player.GameId, &player.UserId, &player.Status, &player.Color, &player.HasPassed); err != nil {
+		return player, errors.New("player not found")
+	}
+	return player, nil
+}
+
+func (s *Store) ListUserGames(userId, afterId, limit int) ([]*models.Game, error) {
+	rows, err := s.db.Query(
+		`SELECT g.id, g.status, g.player_turn_id, g.board_size
+		 FROM games g JOIN players p ON p.game_id = g.id
+		 WHERE p.user_id = $1 AND g.id > $2
+		 ORDER BY g.id LIMIT $3`,
+		userId, afterId, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []*models.Game
+	for rows.Next() {
+		game := &models.Game{Board: &models.Board{}}
+		if err := rows.Scan(&game.Id, &game.Status, &game.PlayerTurnId, &game.Board.Size); err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+
+	return games, nil
+}
@@ -0,0 +1,47 @@
+/*
+Package storage abstracts the persistence operations the resolvers need
+behind a single Repository interface, so the schema in the main package
+can run against Postgres or MongoDB (or anything else) selected once at
+startup, rather than talking to a global database handle directly.
+*/
+package storage
+
+import (
+	"context"
+
+	"github.com/camirmas/go_stop/models"
+)
+
+// Repository is implemented by every storage backend the server can run
+// against.
+type Repository interface {
+	CreateUser(user *models.User) error
+	GetUserByUsername(username string) (*models.User, error)
+	GetUserById(id int) (*models.User, error)
+	CreateGame(game *models.Game) error
+	GetGame(id int) (*models.Game, error)
+	UpdateGame(game *models.Game) error
+	AddStone(gameId int, stone models.Stone) error
+	AddMove(gameId int, move models.Move) error
+	CreatePlayer(player *models.Player) error
+	GetPlayer(id int) (models.Player, error)
+	SetPlayerPassed(playerId int, passed bool) error
+	ListUserGames(userId, afterId, limit int) ([]*models.Game, error)
+}
+
+type contextKey struct{}
+
+var repositoryKey = contextKey{}
+
+// NewContext returns a copy of ctx carrying repo, for a resolver to read
+// back with FromContext.
+func NewContext(ctx context.Context, repo Repository) context.Context {
+	return context.WithValue(ctx, repositoryKey, repo)
+}
+
+// FromContext extracts the Repository injected by NewContext, or nil if
+// none was set.
+func FromContext(ctx context.Context) Repository {
+	repo, _ := ctx.Value(repositoryKey).(Repository)
+	return repo
+}
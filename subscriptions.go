@@ -0,0 +1,98 @@
+package main
+
+import (
+	"github.com/camirmas/go_stop/models"
+	"github.com/camirmas/go_stop/rules"
+	"github.com/graphql-go/graphql"
+)
+
+// gameIdArg is shared by every subscription field; all of them scope
+// delivery to a single game.
+var gameIdArg = graphql.FieldConfigArgument{
+	"gameId": &graphql.ArgumentConfig{
+		Type: graphql.NewNonNull(graphql.ID),
+	},
+}
+
+var subscriptionType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Subscription",
+	Fields: graphql.Fields{
+		"gameUpdated": &graphql.Field{
+			Type: gameType,
+			Args: gameIdArg,
+			Subscribe: subscribeGame(func(data interface{}) (interface{}, bool) {
+				game, ok := data.(*models.Game)
+				return game, ok
+			}),
+			Resolve: resolveSubscriptionPayload,
+		},
+		"moveMade": &graphql.Field{
+			Type: stoneType,
+			Args: gameIdArg,
+			Subscribe: subscribeGame(func(data interface{}) (interface{}, bool) {
+				stone, ok := data.(models.Stone)
+				return stone, ok
+			}),
+			Resolve: resolveSubscriptionPayload,
+		},
+		"gameEnded": &graphql.Field{
+			Type: gameType,
+			Args: gameIdArg,
+			Subscribe: subscribeGame(func(data interface{}) (interface{}, bool) {
+				ended, ok := data.(models.GameEndedEvent)
+				if !ok {
+					return nil, false
+				}
+				return ended.Game, true
+			}),
+			Resolve: resolveSubscriptionPayload,
+		},
+	},
+})
+
+// resolveSubscriptionPayload passes each event straight through:
+// graphql-go's subscription executor re-runs Execute once per event with
+// the payload as the operation's root, then resolves the field's own
+// sub-selection (id, status, ...) against whatever this returns.
+func resolveSubscriptionPayload(p graphql.ResolveParams) (interface{}, error) {
+	return p.Source, nil
+}
+
+// subscribeGame returns a Subscribe function that opens rules.Hub's topic
+// for the requested game and forwards only the events match accepts,
+// unwrapped to the payload the field's type expects. graphql-go resolves
+// a subscription field once per subscribe operation and expects a Go
+// channel back; the transport drains it until it closes and relies on
+// the request context being cancelled on disconnect to trigger
+// unsubscribe.
+func subscribeGame(match func(data interface{}) (interface{}, bool)) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		id, err := parseGameId(p.Args["gameId"].(string))
+		if err != nil {
+			return nil, err
+		}
+
+		events, unsubscribe := rules.Hub.Subscribe(id)
+
+		out := make(chan interface{})
+		go func() {
+			defer close(out)
+			for {
+				select {
+				case <-p.Context.Done():
+					unsubscribe()
+					return
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					if payload, ok := match(event.Data); ok {
+						out <- payload
+					}
+				}
+			}
+		}()
+
+		return out, nil
+	}
+}
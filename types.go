@@ -2,18 +2,74 @@ package main
 
 import (
 	"github.com/camirmas/go_stop/models"
+	"github.com/camirmas/go_stop/relay"
+	"github.com/camirmas/go_stop/resolvers"
+	"github.com/camirmas/go_stop/rules"
+	"github.com/camirmas/go_stop/sgf"
 	"github.com/graphql-go/graphql"
 )
 
+// nodeInterface implements the Relay Node spec: any type exposing a
+// globally-unique id can be refetched through the top-level node query.
+//
+// ResolveType is assigned in init() rather than in this literal: it needs
+// to return userType/playerType/gameType, and those in turn declare
+// nodeInterface in their own Interfaces list, so referencing them here
+// directly would make nodeInterface and the object types depend on each
+// other at package-variable initialization time.
+var nodeInterface = graphql.NewInterface(graphql.InterfaceConfig{
+	Name: "Node",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+	},
+})
+
+func init() {
+	nodeInterface.ResolveType = func(p graphql.ResolveTypeParams) *graphql.Object {
+		switch p.Value.(type) {
+		case *models.User:
+			return userType
+		case models.Player:
+			return playerType
+		case *models.Game:
+			return gameType
+		}
+		return nil
+	}
+
+	// userType.games is added here rather than in its Fields literal: it
+	// returns gameConnectionType, which (via gameEdgeType -> gameType ->
+	// playerType) refers back to userType, so assigning it inline would
+	// create the same kind of initialization cycle nodeInterface.ResolveType
+	// avoids above.
+	userType.AddFieldConfig("games", &graphql.Field{
+		Type: gameConnectionType,
+		Args: graphql.FieldConfigArgument{
+			"first": &graphql.ArgumentConfig{Type: graphql.Int},
+			"after": &graphql.ArgumentConfig{Type: graphql.String},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			user, ok := p.Source.(*models.User)
+			if !ok {
+				return nil, nil
+			}
+			first, _ := p.Args["first"].(int)
+			after, _ := p.Args["after"].(string)
+			return resolvers.UserGames(p, user.Id, first, after)
+		},
+	})
+}
+
 var (
 	userType = graphql.NewObject(graphql.ObjectConfig{
-		Name: "User",
+		Name:       "User",
+		Interfaces: []*graphql.Interface{nodeInterface},
 		Fields: graphql.Fields{
 			"id": &graphql.Field{
 				Type: graphql.NewNonNull(graphql.ID),
 				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 					if user, ok := p.Source.(*models.User); ok {
-						return user.Id, nil
+						return relay.ToGlobalID("User", user.Id), nil
 					}
 					return nil, nil
 				},
@@ -40,13 +96,14 @@ var (
 	})
 
 	playerType = graphql.NewObject(graphql.ObjectConfig{
-		Name: "Player",
+		Name:       "Player",
+		Interfaces: []*graphql.Interface{nodeInterface},
 		Fields: graphql.Fields{
 			"id": &graphql.Field{
-				Type: graphql.ID,
+				Type: graphql.NewNonNull(graphql.ID),
 				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 					if player, ok := p.Source.(models.Player); ok {
-						return player.Id, nil
+						return relay.ToGlobalID("Player", player.Id), nil
 					}
 					return nil, nil
 				},
@@ -145,25 +202,83 @@ var (
 				},
 			},
 			"stones": &graphql.Field{
-				Type: graphql.NewList(stoneType),
+				Type: stoneConnectionType,
+				Args: graphql.FieldConfigArgument{
+					"first": &graphql.ArgumentConfig{Type: graphql.Int},
+					"after": &graphql.ArgumentConfig{Type: graphql.String},
+				},
 				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-					if board, ok := p.Source.(*models.Board); ok {
-						return board.Stones, nil
+					board, ok := p.Source.(*models.Board)
+					if !ok {
+						return nil, nil
 					}
-					return nil, nil
+					return stoneConnection(board.Stones, p.Args)
 				},
 			},
 		},
 	})
 
+	pageInfoType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"endCursor":   &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	stoneEdgeType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "StoneEdge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"node":   &graphql.Field{Type: stoneType},
+		},
+	})
+
+	stoneConnectionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "StoneConnection",
+		Fields: graphql.Fields{
+			"edges":    &graphql.Field{Type: graphql.NewList(stoneEdgeType)},
+			"pageInfo": &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+		},
+	})
+
+	gameEdgeType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "GameEdge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"node":   &graphql.Field{Type: gameType},
+		},
+	})
+
+	gameConnectionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "GameConnection",
+		Fields: graphql.Fields{
+			"edges":    &graphql.Field{Type: graphql.NewList(gameEdgeType)},
+			"pageInfo": &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+		},
+	})
+
+	scoreType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Score",
+		Fields: graphql.Fields{
+			"blackTerritory": &graphql.Field{Type: graphql.Int},
+			"whiteTerritory": &graphql.Field{Type: graphql.Int},
+			"blackCaptures":  &graphql.Field{Type: graphql.Int},
+			"whiteCaptures":  &graphql.Field{Type: graphql.Int},
+			"blackTotal":     &graphql.Field{Type: graphql.Float},
+			"whiteTotal":     &graphql.Field{Type: graphql.Float},
+		},
+	})
+
 	gameType = graphql.NewObject(graphql.ObjectConfig{
-		Name: "Game",
+		Name:       "Game",
+		Interfaces: []*graphql.Interface{nodeInterface},
 		Fields: graphql.Fields{
 			"id": &graphql.Field{
-				Type: graphql.ID,
+				Type: graphql.NewNonNull(graphql.ID),
 				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 					if game, ok := p.Source.(*models.Game); ok {
-						return game.Id, nil
+						return relay.ToGlobalID("Game", game.Id), nil
 					}
 					return nil, nil
 				},
@@ -204,6 +319,62 @@ var (
 					return nil, nil
 				},
 			},
+			"finalScore": &graphql.Field{
+				Type: scoreType,
+				Args: graphql.FieldConfigArgument{
+					"rule": &graphql.ArgumentConfig{
+						Type:         graphql.String,
+						DefaultValue: rules.Japanese,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					game, ok := p.Source.(*models.Game)
+					if !ok {
+						return nil, nil
+					}
+
+					blackTerritory, whiteTerritory, blackCaptures, whiteCaptures, _, err := rules.Score(game.Board)
+					if err != nil {
+						return nil, err
+					}
+
+					blackTotal, whiteTotal, err := rules.FinalScore(game.Board, p.Args["rule"].(string), game.Komi)
+					if err != nil {
+						return nil, err
+					}
+
+					return map[string]interface{}{
+						"blackTerritory": blackTerritory,
+						"whiteTerritory": whiteTerritory,
+						"blackCaptures":  blackCaptures,
+						"whiteCaptures":  whiteCaptures,
+						"blackTotal":     blackTotal,
+						"whiteTotal":     whiteTotal,
+					}, nil
+				},
+			},
+			"deadStones": &graphql.Field{
+				Type:        graphql.NewList(stoneType),
+				Description: "Always empty: automatic dead-stone detection is not implemented yet.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					game, ok := p.Source.(*models.Game)
+					if !ok {
+						return nil, nil
+					}
+					_, _, _, _, dead, err := rules.Score(game.Board)
+					return dead, err
+				},
+			},
+			"sgf": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					game, ok := p.Source.(*models.Game)
+					if !ok {
+						return nil, nil
+					}
+					return sgf.Write(game)
+				},
+			},
 		},
 	})
 
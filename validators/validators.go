@@ -0,0 +1,146 @@
+/*
+Package validators runs field-level checks on mutation input structs
+before a resolver ever touches them, so invalid requests fail with a
+structured, per-field error instead of a generic resolver error.
+*/
+package validators
+
+import (
+	"net/mail"
+
+	"github.com/camirmas/go_stop/models"
+	"github.com/camirmas/go_stop/rules"
+)
+
+// FieldError reports a single invalid field, in the shape clients expect
+// under a VALIDATION error's extensions.fieldErrors.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// CreateUserInput mirrors the CreateUserInput GraphQL input object.
+type CreateUserInput struct {
+	Username             string
+	Email                string
+	Password             string
+	PasswordConfirmation string
+}
+
+// CreateGameInput mirrors the CreateGameInput GraphQL input object.
+type CreateGameInput struct {
+	OpponentUsername string
+	KoRule           string
+}
+
+// AddStoneInput mirrors the AddStoneInput GraphQL input object, plus the
+// target game's BoardSize so coordinates can be checked against its
+// actual board rather than some global ceiling.
+type AddStoneInput struct {
+	GameId    string
+	X         int
+	Y         int
+	BoardSize int
+}
+
+// LogInInput mirrors the LogInInput GraphQL input object.
+type LogInInput struct {
+	Username string
+	Password string
+}
+
+// ValidationError is returned by a resolver when Validate finds one or
+// more invalid fields. graphql-go renders its Extensions into the
+// response error's `extensions`, giving clients a structured
+// { code: "VALIDATION", fieldErrors: [...] } payload instead of a plain
+// message.
+type ValidationError struct {
+	FieldErrors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	return "validation failed"
+}
+
+// Extensions implements graphql-go's gqlerrors.ExtendedError.
+func (e *ValidationError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":        "VALIDATION",
+		"fieldErrors": e.FieldErrors,
+	}
+}
+
+// Validate runs the checks registered for input's concrete type,
+// returning nil if there are none or if input passes all of them.
+func Validate(input interface{}) []FieldError {
+	switch in := input.(type) {
+	case CreateUserInput:
+		return validateCreateUser(in)
+	case CreateGameInput:
+		return validateCreateGame(in)
+	case AddStoneInput:
+		return validateAddStone(in)
+	case LogInInput:
+		return validateLogIn(in)
+	default:
+		return nil
+	}
+}
+
+func validateCreateUser(in CreateUserInput) []FieldError {
+	var errs []FieldError
+
+	if len(in.Username) < 3 {
+		errs = append(errs, FieldError{Field: "username", Message: "must be at least 3 characters"})
+	}
+	if _, err := mail.ParseAddress(in.Email); err != nil {
+		errs = append(errs, FieldError{Field: "email", Message: "must be a valid email address"})
+	}
+	if len(in.Password) < 8 {
+		errs = append(errs, FieldError{Field: "password", Message: "must be at least 8 characters"})
+	}
+	if in.Password != in.PasswordConfirmation {
+		errs = append(errs, FieldError{Field: "passwordConfirmation", Message: "must match password"})
+	}
+
+	return errs
+}
+
+func validateCreateGame(in CreateGameInput) []FieldError {
+	var errs []FieldError
+
+	if in.OpponentUsername == "" {
+		errs = append(errs, FieldError{Field: "opponentUsername", Message: "is required"})
+	}
+	if in.KoRule != "" && in.KoRule != rules.Superko {
+		errs = append(errs, FieldError{Field: "koRule", Message: "must be empty or \"superko\""})
+	}
+
+	return errs
+}
+
+func validateAddStone(in AddStoneInput) []FieldError {
+	var errs []FieldError
+
+	if in.GameId == "" {
+		errs = append(errs, FieldError{Field: "gameId", Message: "is required"})
+	}
+	if !rules.IsInbounds(in.BoardSize, models.Stone{X: in.X, Y: in.Y}) {
+		errs = append(errs, FieldError{Field: "x", Message: "coordinates must be on the board"})
+	}
+
+	return errs
+}
+
+func validateLogIn(in LogInInput) []FieldError {
+	var errs []FieldError
+
+	if in.Username == "" {
+		errs = append(errs, FieldError{Field: "username", Message: "is required"})
+	}
+	if in.Password == "" {
+		errs = append(errs, FieldError{Field: "password", Message: "is required"})
+	}
+
+	return errs
+}
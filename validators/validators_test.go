@@ -0,0 +1,45 @@
+package validators
+
+import "testing"
+
+func TestValidate_CreateUser(t *testing.T) {
+	errs := Validate(CreateUserInput{
+		Username:             "ab",
+		Email:                "not-an-email",
+		Password:             "short",
+		PasswordConfirmation: "different",
+	})
+
+	if len(errs) != 4 {
+		t.Fatalf("got %d field errors, want 4: %+v", len(errs), errs)
+	}
+}
+
+func TestValidate_CreateUser_Valid(t *testing.T) {
+	errs := Validate(CreateUserInput{
+		Username:             "hikaru",
+		Email:                "hikaru@example.com",
+		Password:             "password123",
+		PasswordConfirmation: "password123",
+	})
+
+	if len(errs) != 0 {
+		t.Fatalf("got %+v, want no field errors", errs)
+	}
+}
+
+func TestValidate_AddStone_OutOfBounds(t *testing.T) {
+	errs := Validate(AddStoneInput{GameId: "1", X: -1, Y: 0, BoardSize: 19})
+
+	if len(errs) != 1 || errs[0].Field != "x" {
+		t.Fatalf("got %+v, want a single x field error", errs)
+	}
+}
+
+func TestValidate_AddStone_OutOfBoardSize(t *testing.T) {
+	errs := Validate(AddStoneInput{GameId: "1", X: 12, Y: 12, BoardSize: 9})
+
+	if len(errs) != 1 || errs[0].Field != "x" {
+		t.Fatalf("got %+v, want a single x field error for a coordinate outside a 9x9 board", errs)
+	}
+}